@@ -158,6 +158,58 @@ func toProtoCamelCase(public bool, head string, parts ...string) string {
 	return escapeReserveds(buf.String())
 }
 
+// protocGoName converts a proto name into a Go one the same way GoCamelCase
+// from google.golang.org/protobuf/compiler/protogen does. It splits the name
+// into words the protoc-gen-go way and puts them through Proto: a word also
+// starts after a digit and underscores followed by anything but a lower
+// case letter are kept.
+func protocGoName(s string) string {
+	isLower := func(c byte) bool { return 'a' <= c && c <= 'z' }
+	isDigit := func(c byte) bool { return '0' <= c && c <= '9' }
+
+	var words []string
+	var word []byte
+	next := func() {
+		if len(word) > 0 {
+			words = append(words, string(word))
+			word = word[:0]
+		}
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '.' && i+1 < len(s) && isLower(s[i+1]):
+			next()
+		case c == '.':
+			next()
+			word = append(word, '_')
+		case c == '_' && (i == 0 || s[i-1] == '.'):
+			// Initial '_' is converted to make the name exported.
+			next()
+			word = append(word, 'X')
+			next()
+		case c == '_' && i+1 < len(s) && isLower(s[i+1]):
+			next()
+		case c == '_':
+			next()
+			word = append(word, c)
+		case isDigit(c):
+			word = append(word, c)
+			if i+1 < len(s) && isLower(s[i+1]) {
+				next()
+			}
+		default:
+			word = append(word, c)
+		}
+	}
+	next()
+
+	if len(words) == 0 {
+		return ""
+	}
+	return Proto(words[0], words[1:]...)
+}
+
 func upMapMatch(upMap []bool, pattern ...bool) bool {
 	if len(upMap) < len(pattern) {
 		return false
@@ -36,3 +36,54 @@ func TestPrivate(t *testing.T) {
 		})
 	}
 }
+
+func TestProtocGoName(t *testing.T) {
+	tests := []struct {
+		arg  string
+		want string
+	}{
+		{
+			arg:  "user_id",
+			want: "UserId",
+		},
+		{
+			arg:  "UInt64Value",
+			want: "UInt64Value",
+		},
+		{
+			arg:  "foo2bar",
+			want: "Foo2Bar",
+		},
+		{
+			arg:  "user_ID",
+			want: "User_ID",
+		},
+		{
+			arg:  "_hidden",
+			want: "XHidden",
+		},
+		{
+			arg:  "foo.bar",
+			want: "FooBar",
+		},
+		{
+			arg:  "foo.Bar",
+			want: "Foo_Bar",
+		},
+		{
+			arg:  "v1_2beta",
+			want: "V1_2Beta",
+		},
+		{
+			arg:  "a__b",
+			want: "A_B",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			if got := protocGoName(tt.arg); got != tt.want {
+				t.Errorf("protocGoName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,5 @@
+package main
+
+const (
+	appName = "goghmock"
+)
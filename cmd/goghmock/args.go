@@ -0,0 +1,55 @@
+package main
+
+import (
+	"go/parser"
+	"strings"
+
+	"github.com/alecthomas/kong"
+	"github.com/sirkon/errors"
+	"github.com/sirkon/gogh"
+)
+
+type arguments struct {
+	Version kong.VersionFlag `help:"Print version and exit." short:"v"`
+
+	Iface sourcePoint `arg:"" help:"Interface to mock. Referenced as <pkgpath>:<typename>."`
+	Dst   sourcePoint `arg:"" help:"Mock type to generate. Referenced as <pkgpath>:<typename>."`
+
+	PackageName string `help:"Package name for the mock. Will be ignored if the package exists already, the last path element is used by default." short:"p"`
+}
+
+// sourcePoint represents a command line argument that looks like
+// <path>:<identifier>.
+type sourcePoint struct {
+	Path string
+	ID   string
+}
+
+// UnmarshalText to implement encoding.TextUnmarshaler.
+func (s *sourcePoint) UnmarshalText(text []byte) error {
+	v := string(text)
+	parts := strings.Split(v, ":")
+	switch len(parts) {
+	case 1:
+		return errors.Newf("missing ':' in '%s'", v)
+	case 2:
+	default:
+		return errors.Newf("path and identifier separated with ':', got %d separated parts instead", len(parts))
+	}
+
+	if parts[0] == "" {
+		return errors.Newf("missing package path in '%s'", v)
+	}
+	if _, err := parser.ParseExpr(parts[1]); err != nil {
+		return errors.Newf("invalid identifier '%s'", parts[1])
+	}
+	if parts[1] != gogh.Public(parts[1]) {
+		return errors.New("do not like the proposed type name").
+			Str("proposed", parts[1]).
+			Str("would-be-good", gogh.Public(parts[1]))
+	}
+
+	s.Path = parts[0]
+	s.ID = parts[1]
+	return nil
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestSourcePointUnmarshalText(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    sourcePoint
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			text: "example.com/project/storage:StorageMock",
+			want: sourcePoint{Path: "example.com/project/storage", ID: "StorageMock"},
+		},
+		{
+			name:    "missing-colon",
+			text:    "example.com/project/storage",
+			wantErr: true,
+		},
+		{
+			name:    "too-many-parts",
+			text:    "example.com/project:storage:StorageMock",
+			wantErr: true,
+		},
+		{
+			name:    "missing-path",
+			text:    ":StorageMock",
+			wantErr: true,
+		},
+		{
+			name:    "invalid-identifier",
+			text:    "example.com/project/storage:Storage Mock",
+			wantErr: true,
+		},
+		{
+			name:    "private-identifier",
+			text:    "example.com/project/storage:storageMock",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got sourcePoint
+			err := got.UnmarshalText([]byte(tt.text))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UnmarshalText() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("UnmarshalText() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
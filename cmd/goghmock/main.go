@@ -0,0 +1,77 @@
+package main
+
+import (
+	"os"
+	"path"
+	"runtime/debug"
+
+	"github.com/alecthomas/kong"
+	"github.com/sirkon/errors"
+	"github.com/sirkon/gogh"
+	"github.com/sirkon/gogh/mocks"
+	"github.com/sirkon/message"
+)
+
+func main() {
+	argParams := os.Args[1:]
+	if len(argParams) > 0 && argParams[0] == "--" {
+		argParams = argParams[1:]
+	}
+
+	var args arguments
+	cliParser := kong.Must(
+		&args,
+		kong.Name(appName),
+		kong.Description("Generate call recording mock for the given interface."),
+		kong.UsageOnError(),
+	)
+
+	if _, err := cliParser.Parse(argParams); err != nil {
+		message.Warning(errors.Wrap(err, "parse command line arguments"))
+		cliParser.FatalIfErrorf(err)
+	}
+
+	if args.Version {
+		var version string
+		info, ok := debug.ReadBuildInfo()
+		if !ok {
+			version = "(devel)"
+		} else {
+			version = info.Main.Version
+		}
+
+		message.Info(appName, "version", version)
+		return
+	}
+
+	iface, err := getInterface(args.Iface)
+	if err != nil {
+		message.Fatal(errors.Wrap(err, "get interface type info"))
+	}
+
+	prj, err := gogh.New(
+		gogh.GoFmt,
+		func(r *gogh.Imports) *gogh.Imports {
+			return r
+		},
+	)
+	if err != nil {
+		message.Fatal(errors.Wrap(err, "set up rendering project"))
+	}
+
+	pkgName := args.PackageName
+	if pkgName == "" {
+		pkgName = path.Base(args.Dst.Path)
+	}
+	p, err := prj.Package(pkgName, args.Dst.Path)
+	if err != nil {
+		message.Fatal(errors.Wrap(err, "set up rendering package").Str("pkg-path", args.Dst.Path))
+	}
+
+	fileName := gogh.Underscored(args.Dst.ID) + ".go"
+	mocks.Render(p.Go(fileName, gogh.Autogen(appName)), args.Dst.ID, iface)
+
+	if err := prj.Render(); err != nil {
+		message.Fatal(errors.Wrap(err, "render generated source code"))
+	}
+}
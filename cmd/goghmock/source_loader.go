@@ -0,0 +1,50 @@
+package main
+
+import (
+	"go/types"
+
+	"github.com/sirkon/errors"
+	"golang.org/x/tools/go/packages"
+)
+
+// getInterface loads the package and looks up the interface type with the given name.
+func getInterface(pnt sourcePoint) (types.Type, error) {
+	pkgs, err := packages.Load(
+		&packages.Config{
+			Mode: packages.NeedName | packages.NeedTypes | packages.NeedImports | packages.NeedDeps,
+		},
+		pnt.Path,
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "load package").Str("pkg-path", pnt.Path)
+	}
+	if len(pkgs) != 1 {
+		return nil, errors.Newf("expected exactly one package, got %d", len(pkgs)).Str("pkg-path", pnt.Path)
+	}
+
+	pkg := pkgs[0]
+	if len(pkg.Errors) > 0 {
+		return nil, errors.Wrap(pkg.Errors[0], "load package").Str("pkg-path", pnt.Path)
+	}
+
+	obj := pkg.Types.Scope().Lookup(pnt.ID)
+	if obj == nil {
+		return nil, errors.New("type was not found").
+			Str("pkg-path", pnt.Path).
+			Str("type-name", pnt.ID)
+	}
+
+	typ, ok := obj.(*types.TypeName)
+	if !ok {
+		return nil, errors.New("an object the name references is not a type").
+			Str("name", pnt.ID).
+			Str("object", obj.String())
+	}
+	if _, ok := typ.Type().Underlying().(*types.Interface); !ok {
+		return nil, errors.New("type is not an interface").
+			Str("name", pnt.ID).
+			Str("type", typ.Type().String())
+	}
+
+	return typ.Type(), nil
+}
@@ -7,6 +7,7 @@ require (
 	github.com/blang/semver/v4 v4.0.0
 	github.com/boltdb/bolt v1.3.1
 	github.com/chonla/roman-number-go v0.0.0-20181101035413-6768129de021
+	github.com/emicklei/proto v1.14.3
 	github.com/sirkon/deepequal v0.5.9
 	github.com/sirkon/errors v1.3.3
 	github.com/sirkon/go-format/v2 v2.0.2
@@ -15,10 +16,10 @@ require (
 	github.com/sirkon/protoast/v2 v2.3.2
 	golang.org/x/exp v0.0.0-20230510235704-dd950f8aeaea
 	golang.org/x/tools v0.9.1
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
-	github.com/emicklei/proto v1.14.3 // indirect
 	github.com/golang/protobuf v1.5.2 // indirect
 	github.com/lestrrat/go-strftime v0.0.0-20180220042222-ba3bf9c1d042 // indirect
 	github.com/pkg/errors v0.8.1 // indirect
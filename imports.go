@@ -32,6 +32,9 @@ type Imports struct {
 	namer     func(relpath string) string
 	pending   []*ImportAliasControl
 	corrector AliasCorrector
+
+	// docs packages imported by doc links, see pruneDocImports.
+	docs map[string]struct{}
 }
 
 // Imports to satisfy Importer
@@ -151,6 +154,11 @@ func (a *ImportAliasControl) Ref(ref string) {
 	}
 }
 
+// Alias registers the import and returns a package name or alias it is referenced with.
+func (a *ImportAliasControl) Alias() string {
+	return a.push()
+}
+
 func (a *ImportAliasControl) push() string {
 	if v, ok := a.i.pkgs[a.pkgpath]; ok {
 		return v
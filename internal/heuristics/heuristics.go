@@ -6,22 +6,25 @@ package heuristics
 // some elements may already be computed using more
 // reliable methods. These should not be replaced.
 //
-// Return nil slice only if kind "unclear" detected on any type.
+// Zero values of types whose kind is "unclear" are left empty,
+// it is up to the caller to find them out in some other way.
 func ZeroGuesses(ps [][2]string, zeroes []string) []string {
-	var res []string
+	res := make([]string, 0, len(ps))
 	if len(zeroes) == 0 {
 		zeroes = make([]string, len(ps))
 	}
 
 	for i, p := range ps {
 		if zeroes[i] != "" {
+			res = append(res, zeroes[i])
 			continue
 		}
 
 		k := guessKind(p[1])
 		switch k {
 		case unclear:
-			return nil
+			res = append(res, "")
+			continue
 		case errortype:
 			if i != len(ps)-1 {
 				res = append(res, "nil")
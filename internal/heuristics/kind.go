@@ -18,6 +18,7 @@ const (
 	maptype
 	pointer
 	channel
+	nilable
 	errortype
 	unclear
 )
@@ -28,13 +29,17 @@ func guessKind(typ string) kind {
 		return boolean
 	case "string":
 		return str
-	case "byte", "uintptr", "uint", "int":
+	case "byte", "rune", "uintptr", "uint", "int":
 		return number
 	case "error":
 		return errortype
+	case "any":
+		return nilable
 	}
 
 	switch {
+	case strings.HasPrefix(typ, "func("), strings.HasPrefix(typ, "interface{"):
+		return nilable
 	case strings.HasPrefix(typ, "int"):
 		return number
 	case strings.HasPrefix(typ, "uint"):
@@ -53,7 +58,7 @@ func guessKind(typ string) kind {
 		return maptype
 	case strings.HasPrefix(typ, "*"):
 		return pointer
-	case strings.HasPrefix(typ, "chan"):
+	case strings.HasPrefix(typ, "chan"), strings.HasPrefix(typ, "<-chan"):
 		return channel
 	default:
 		return unclear
@@ -70,7 +75,7 @@ func kindZero(typ string, v kind) string {
 		return `""`
 	case array:
 		return typ + "{}"
-	case slice, maptype, pointer, channel:
+	case slice, maptype, pointer, channel, nilable:
 		return "nil"
 	case errortype:
 		return consts.ErrorTypeZeroSign
@@ -0,0 +1,3 @@
+// Package mocks renders call recording mocks of interfaces with gogh.
+// Rendered code depends on the standard library only.
+package mocks
@@ -0,0 +1,328 @@
+package mocks
+
+import (
+	"go/types"
+	"strings"
+
+	"github.com/sirkon/errors"
+
+	"github.com/sirkon/gogh"
+)
+
+// Render renders a mock type with the given name for the given interface.
+// The iface is either *types.Interface or a named interface type.
+//
+// The mock provides for each method M of the interface:
+//
+//   - ExpectM(args...) to register an expectation of a call. Each
+//     argument is either an expected value, which is compared with
+//     reflect.DeepEqual, or a func(T) bool matcher, or <Name>Any
+//     to match any value. Function values are not comparable, so
+//     arguments of function types need a matcher or <Name>Any.
+//   - Return(results...), Do(f), Times(n) and AnyTimes() methods of
+//     an expectation to set up results and an expected calls count.
+//   - MCalls() returning a number of M calls made.
+//
+// Unexpected calls fail the test immediately, unmet expectations are
+// reported on the test cleanup.
+func Render[T gogh.Importer](r *gogh.GoRenderer[T], name string, iface types.Type) {
+	it := interfaceOf(iface)
+
+	r = r.Scope()
+	r.Imports().Add("testing").Ref("testing")
+	r.Imports().Add("sync").Ref("sync")
+	r.Imports().Add("reflect").Ref("reflect")
+	r.Let("mock", name)
+	r.Let("iface", r.Type(iface))
+	r.Let("any", name+"Any")
+	r.Let("anyType", gogh.Private(name, "any"))
+	r.Let("matcher", gogh.Private(name, "matcher"))
+
+	// Mock methods must not clash with ones generated.
+	methods := r.Scope()
+	for i := 0; i < it.NumMethods(); i++ {
+		methods.Uniq(it.Method(i).Name())
+	}
+	for i := 0; i < it.NumMethods(); i++ {
+		m := it.Method(i)
+		for _, n := range []string{"Expect" + m.Name(), m.Name() + "Calls"} {
+			if methods.Uniq(n) != n {
+				panic(errors.Newf("mock method %s clashes with the method of %s", n, r.S("$iface")))
+			}
+		}
+	}
+
+	r.L(`// $mock is a call recording mock of $iface.`)
+	r.L(`type $mock struct {`)
+	r.L(`    t $testing.TB`)
+	r.L(`    lock $sync.Mutex`)
+	r.N()
+	r.L(`    expectations struct {`)
+	fields := r.Z()
+	r.L(`    }`)
+	r.L(`    calls struct {`)
+	counters := r.Z()
+	r.L(`    }`)
+	r.L(`}`)
+	r.N()
+	r.L(`// New$mock creates $mock. Unmet expectations are reported on the test cleanup.`)
+	r.L(`func New$mock(t $testing.TB) *$mock {`)
+	r.L(`    m := &$mock{t: t}`)
+	r.L(`    t.Cleanup(m.check)`)
+	r.L(`    return m`)
+	r.L(`}`)
+	r.N()
+	r.L(`var _ $iface = (*$mock)(nil)`)
+	r.N()
+	r.L(`// $any matches any argument value.`)
+	r.L(`var $any $anyType`)
+	r.N()
+	r.L(`type $anyType struct{}`)
+
+	for i := 0; i < it.NumMethods(); i++ {
+		m := it.Method(i)
+		fields.L(`$0 []*$mock$0Call`, m.Name())
+		counters.L(`$0 int`, m.Name())
+		r.N()
+		renderMethod(r.Scope(), m)
+	}
+
+	r.N()
+	r.L(`func (m *$mock) check() {`)
+	r.L(`    m.t.Helper()`)
+	r.L(`    m.lock.Lock()`)
+	r.L(`    defer m.lock.Unlock()`)
+	r.N()
+	for i := 0; i < it.NumMethods(); i++ {
+		r.L(`    for _, c := range m.expectations.$0 {`, it.Method(i).Name())
+		r.L(`        if c.times >= 0 && c.calls != c.times {`)
+		r.L(`            m.t.Errorf("$0 is expected to be called %d times with matching arguments, got %d", c.times, c.calls)`, it.Method(i).Name())
+		r.L(`        }`)
+		r.L(`    }`)
+	}
+	r.L(`}`)
+	r.N()
+	r.L(`func $matcher[T any](t $testing.TB, name string, arg any) func(T) bool {`)
+	r.L(`    t.Helper()`)
+	r.N()
+	r.L(`    switch v := arg.(type) {`)
+	r.L(`    case $anyType:`)
+	r.L(`        return func(T) bool { return true }`)
+	r.L(`    case func(T) bool:`)
+	r.L(`        return v`)
+	r.L(`    case T:`)
+	r.L(`        if $reflect.ValueOf(v).Kind() == $reflect.Func {`)
+	r.L(`            t.Fatalf("argument %s matcher cannot be a function value as functions are not comparable, use func(%T) bool or $any", name, v)`)
+	r.L(`        }`)
+	r.L(`        return func(x T) bool { return $reflect.DeepEqual(x, v) }`)
+	r.L(`    case nil:`)
+	r.L(`        return func(x T) bool { return $reflect.ValueOf(&x).Elem().IsZero() }`)
+	r.L(`    default:`)
+	r.L(`        var x T`)
+	r.L(`        t.Fatalf("argument %s matcher must be either %T or func(%T) bool, got %T", name, x, x, arg)`)
+	r.L(`        return nil`)
+	r.L(`    }`)
+	r.L(`}`)
+}
+
+func renderMethod[T gogh.Importer](r *gogh.GoRenderer[T], m *types.Func) {
+	sig := m.Type().(*types.Signature)
+
+	r.Let("method", m.Name())
+	r.Let("call", r.S("$mock${method}Call"))
+
+	names := r.Scope()
+	names.Uniq("m")
+	names.Uniq("c")
+	var params gogh.Params
+	var args []string
+	var argTypes []string
+	var anyParams gogh.Params
+	for i := 0; i < sig.Params().Len(); i++ {
+		p := sig.Params().At(i)
+		name := p.Name()
+		if name == "" || name == "_" {
+			name = "arg"
+		}
+		name = names.Uniq(name)
+		args = append(args, name)
+		anyParams.Add(name, "any")
+
+		typ := r.Type(p.Type())
+		argTypes = append(argTypes, typ)
+		if sig.Variadic() && i == sig.Params().Len()-1 {
+			params.Add(name, "..."+r.Type(p.Type().(*types.Slice).Elem()))
+			continue
+		}
+		params.Add(name, typ)
+	}
+
+	var results gogh.Params
+	var rets []string
+	var retTypes []string
+	for i := 0; i < sig.Results().Len(); i++ {
+		p := sig.Results().At(i)
+		name := p.Name()
+		if name == "" || name == "_" {
+			name = "res"
+			if i == sig.Results().Len()-1 && p.Type().String() == "error" {
+				name = "err"
+			}
+		}
+		name = names.Uniq(name)
+		rets = append(rets, name)
+		retTypes = append(retTypes, r.Type(p.Type()))
+		results.Add(name, retTypes[i])
+	}
+
+	r.Let("params", params)
+	r.Let("funcType", r.S("func($params) ($0)", strings.Join(retTypes, ", ")))
+
+	r.L(`// $call is an expectation of $method call.`)
+	r.L(`type $call struct {`)
+	if len(args) > 0 {
+		r.L(`    args struct {`)
+		for i, arg := range args {
+			r.L(`        $0 func($1) bool`, arg, argTypes[i])
+		}
+		r.L(`    }`)
+	}
+	if len(rets) > 0 {
+		r.L(`    results struct {`)
+		for i, res := range rets {
+			r.L(`        $0 $1`, res, retTypes[i])
+		}
+		r.L(`    }`)
+	}
+	r.L(`    do $funcType`)
+	r.L(`    times int`)
+	r.L(`    calls int`)
+	r.L(`}`)
+	r.N()
+
+	r.L(`// Expect$method registers an expectation of $method call with matching arguments.`)
+	r.L(`// The expectation is met after a single call by default.`)
+	r.L(`func (m *$mock) Expect$method($0) *$call {`, &anyParams)
+	r.L(`    m.t.Helper()`)
+	r.L(`    c := &$call{times: 1}`)
+	for i, arg := range args {
+		r.L(`    c.args.$0 = $matcher[$1](m.t, "$0", $0)`, arg, argTypes[i])
+	}
+	r.N()
+	r.L(`    m.lock.Lock()`)
+	r.L(`    defer m.lock.Unlock()`)
+	r.L(`    m.expectations.$method = append(m.expectations.$method, c)`)
+	r.L(`    return c`)
+	r.L(`}`)
+	r.N()
+
+	if len(rets) > 0 {
+		r.L(`// Return sets values to be returned by a matching call.`)
+		r.L(`func (c *$call) Return($0) *$call {`, &results)
+		for _, res := range rets {
+			r.L(`    c.results.$0 = $0`, res)
+		}
+		r.L(`    return c`)
+		r.L(`}`)
+		r.N()
+	}
+
+	r.L(`// Do sets a function to be called with arguments of a matching call,`)
+	r.L(`// its results are returned instead of ones set with Return.`)
+	r.L(`func (c *$call) Do(f $funcType) *$call {`)
+	r.L(`    c.do = f`)
+	r.L(`    return c`)
+	r.L(`}`)
+	r.N()
+	r.L(`// Times sets an expected number of calls.`)
+	r.L(`func (c *$call) Times(n int) *$call {`)
+	r.L(`    c.times = n`)
+	r.L(`    return c`)
+	r.L(`}`)
+	r.N()
+	r.L(`// AnyTimes allows any number of calls, including none.`)
+	r.L(`func (c *$call) AnyTimes() *$call {`)
+	r.L(`    c.times = -1`)
+	r.L(`    return c`)
+	r.L(`}`)
+	r.N()
+	r.L(`// $0 returns the number of $method calls made.`, m.Name()+"Calls")
+	r.L(`func (m *$mock) ${method}Calls() int {`)
+	r.L(`    m.lock.Lock()`)
+	r.L(`    defer m.lock.Unlock()`)
+	r.L(`    return m.calls.$method`)
+	r.L(`}`)
+	r.N()
+
+	var callArgs []string
+	var matches []string
+	var formats []string
+	for i, arg := range args {
+		callArgs = append(callArgs, arg)
+		matches = append(matches, r.S(`c.args.$0($0)`, arg))
+		if sig.Variadic() && i == len(args)-1 {
+			callArgs[i] += "..."
+		}
+
+		// Function values cannot be printed meaningfully, only their addresses.
+		if _, ok := sig.Params().At(i).Type().Underlying().(*types.Signature); ok {
+			formats = append(formats, "%p")
+		} else {
+			formats = append(formats, "%#v")
+		}
+	}
+
+	r.L(`// $method to implement $iface.`)
+	r.L(`func (m *$mock) $method($params) ($0) {`, strings.Join(retTypes, ", "))
+	r.L(`    m.t.Helper()`)
+	r.L(`    m.lock.Lock()`)
+	r.L(`    m.calls.$method++`)
+	r.N()
+	r.L(`    for _, c := range m.expectations.$method {`)
+	r.L(`        if c.times >= 0 && c.calls >= c.times {`)
+	r.L(`            continue`)
+	r.L(`        }`)
+	if len(matches) > 0 {
+		r.L(`        if !($0) {`, strings.Join(matches, " && "))
+		r.L(`            continue`)
+		r.L(`        }`)
+	}
+	r.N()
+	r.L(`        c.calls++`)
+	r.L(`        m.lock.Unlock()`)
+	r.L(`        if c.do != nil {`)
+	if len(rets) > 0 {
+		r.L(`            return c.do($0)`, strings.Join(callArgs, ", "))
+	} else {
+		r.L(`            c.do($0)`, strings.Join(callArgs, ", "))
+		r.L(`            return`)
+	}
+	r.L(`        }`)
+	if len(rets) > 0 {
+		var rs []string
+		for _, res := range rets {
+			rs = append(rs, "c.results."+res)
+		}
+		r.L(`        return $0`, strings.Join(rs, ", "))
+	} else {
+		r.L(`        return`)
+	}
+	r.L(`    }`)
+	r.L(`    m.lock.Unlock()`)
+	r.N()
+	if len(args) > 0 {
+		r.L(`    m.t.Fatalf("unexpected call $method($0)", $1)`, strings.Join(formats, ", "), strings.Join(args, ", "))
+	} else {
+		r.L(`    m.t.Fatalf("unexpected call $method()")`)
+	}
+	r.L(`    panic("unreachable")`)
+	r.L(`}`)
+}
+
+func interfaceOf(t types.Type) *types.Interface {
+	if v, ok := t.Underlying().(*types.Interface); ok {
+		return v
+	}
+
+	panic(errors.Newf("type %s is not an interface", t))
+}
@@ -0,0 +1,95 @@
+package mocks
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sirkon/gogh"
+)
+
+const storeSource = `package mocktest
+
+type Store[K comparable, V any] interface {
+	Load(key K, fallback func() V) (V, error)
+	Keys(prefixes ...string) []K
+}
+`
+
+// TestRender renders a mock of an instantiated generic interface with
+// function and variadic parameters and runs tests of the mock against it.
+func TestRender(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":   "module example.com/mocktest\n\ngo 1.26\n",
+		"store.go": storeSource,
+	}
+	test, err := os.ReadFile(filepath.Join("testdata", "store_mock_test.go.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files["store_mock_test.go"] = string(test)
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "store.go", storeSource, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := types.Config{Importer: importer.Default()}
+	pkg, err := cfg.Check("example.com/mocktest", fset, []*ast.File{file}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store, err := types.Instantiate(
+		nil,
+		pkg.Scope().Lookup("Store").Type(),
+		[]types.Type{types.Typ[types.String], types.Typ[types.Int]},
+		true,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Keep the build cache while gogh cache goes into a temporary directory.
+	gocache, err := exec.Command("go", "env", "GOCACHE").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GOCACHE", strings.TrimSpace(string(gocache)))
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Chdir(dir)
+	prj, err := gogh.New(gogh.GoFmt, func(r *gogh.Imports) *gogh.Imports {
+		return r
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := prj.Root("mocktest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	Render(p.Go("store_mock.go"), "StoreMock", store)
+	if err := prj.Render(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, args := range [][]string{{"vet", "."}, {"test", "-count=1", "."}} {
+		cmd := exec.Command("go", args...)
+		cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOWORK=off")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Errorf("go %s failed: %s\n%s", args[0], err, out)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"go/types"
 	"os"
 	"path"
 	"path/filepath"
@@ -91,13 +92,26 @@ type Module[T Importer] struct {
 	deps           map[string]semver.Version
 	fixedDeps      map[string]semver.Version
 	registry       *protoast.Registry
-
-	pkgs map[string]*Package[T]
-	raws map[string]*RawRenderer
-
-	pkgcache   map[string]string
-	bolt       *bolt.DB
-	goghBucket []byte
+	protoResolvers []protoast.PathResolver
+	protoMapping   map[string]string
+	docWidth       int
+	errWrapper     ErrorWrapper
+	license        string
+	defaultOpts    []RendererOption
+	inputs         []Input
+	fingerprint    string
+
+	pkgs   map[string]*Package[T]
+	raws   map[string]*RawRenderer
+	protos map[string]*ProtoRenderer
+
+	pkgcache      map[string]string
+	typesCache    map[string]typesCacheItem
+	typesImporter types.ImporterFrom
+	protoPkgs     map[string]protoGoPackage
+	protoSources  map[string]*protoSource
+	bolt          *bolt.DB
+	goghBucket    []byte
 }
 
 // Root create if needed and returns a package placed right in the project root. The name parameter is rather
@@ -193,18 +207,17 @@ func (m *Module[T]) PackageName(pkgpath string) (string, error) {
 
 // Raw creates a renderer for plain text file
 func (m *Module[T]) Raw(relpath string, opts ...RendererOption) *RawRenderer {
+	return m.raw(relpath, m.defaultOpts, opts)
+}
+
+func (m *Module[T]) raw(relpath string, defaults, opts []RendererOption) *RawRenderer {
 	fullpath := filepath.Join(m.root, relpath)
 	localpath := path.Join(m.name, relpath)
 	if v, ok := m.raws[relpath]; ok {
 		return v
 	}
 
-	res := &RawRenderer{
-		localname: localpath,
-		fullname:  fullpath,
-		options:   opts,
-		vals:      map[string]any{},
-	}
+	res := newRawRenderer(m.root, localpath, fullpath, rendererOptions(defaults, opts))
 	m.raws[relpath] = res
 	return res
 }
@@ -214,7 +227,8 @@ func (m *Module[T]) Name() string {
 	return m.name
 }
 
-// Render renders generated data
+// Render renders generated data. Files whose inputs declared with WithInputs
+// have not changed since the last run are not rendered again.
 func (m *Module[T]) Render() (err error) {
 	defer func() {
 		if err := m.bolt.Close(); err != nil {
@@ -222,10 +236,23 @@ func (m *Module[T]) Render() (err error) {
 		}
 	}()
 
+	return m.render()
+}
+
+func (m *Module[T]) render() error {
+	tracker, err := m.renderTracker()
+	if err != nil {
+		return errors.Wrap(err, "set up inputs tracking")
+	}
+
 	for pkgpath, pkg := range m.pkgs {
 		for name, r := range pkg.rs {
-			fullname := filepath.Join(m.root, pkgpath, name)
-			localname := filepath.Join(m.name, pkgpath, name)
+			relpath := filepath.Join(pkgpath, name)
+			fullname := filepath.Join(m.root, relpath)
+			localname := filepath.Join(m.name, relpath)
+			if tracker.skip(relpath) {
+				continue
+			}
 
 			if err := os.MkdirAll(filepath.Dir(fullname), 0755); err != nil {
 				return errors.Wrap(err, "create a directory for "+localname)
@@ -234,10 +261,15 @@ func (m *Module[T]) Render() (err error) {
 			if err := r.render(); err != nil {
 				return errors.Wrap(err, "renders "+localname)
 			}
+			tracker.done(relpath)
 		}
 	}
 
-	for _, r := range m.raws {
+	for relpath, r := range m.raws {
+		if tracker.skip(relpath) {
+			continue
+		}
+
 		if err := os.MkdirAll(filepath.Dir(r.fullname), 0755); err != nil {
 			return errors.Wrap(err, "create a directory for "+r.localname)
 		}
@@ -245,9 +277,10 @@ func (m *Module[T]) Render() (err error) {
 		if err := r.render(); err != nil {
 			return errors.Wrap(err, "renders "+r.localname)
 		}
+		tracker.done(relpath)
 	}
 
-	return nil
+	return m.saveRenderTracker(tracker)
 }
 
 func (m *Module[T]) getPackage(name, pkgpath string) (*Package[T], error) {
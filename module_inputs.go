@@ -0,0 +1,258 @@
+package gogh
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/sirkon/errors"
+	"github.com/sirkon/jsonexec"
+)
+
+// Input is a generator input declared with WithInputs.
+type Input struct {
+	kind  string
+	value string
+	extra string
+}
+
+// InputGlob declares files matching the pattern as inputs. The pattern
+// syntax is the one of filepath.Match, relative patterns are resolved
+// against the module root.
+func InputGlob(pattern string) Input {
+	return Input{
+		kind:  "glob",
+		value: pattern,
+	}
+}
+
+// InputPackage declares Go files of the package as inputs. It can be any
+// package go list can find.
+func InputPackage(pkgpath string) Input {
+	return Input{
+		kind:  "package",
+		value: pkgpath,
+	}
+}
+
+// InputKey declares an arbitrary named value as an input, generator flags
+// or a config version for instance.
+func InputKey(key, value string) Input {
+	return Input{
+		kind:  "key",
+		value: key,
+		extra: value,
+	}
+}
+
+// inputsManifest is kept in the bolt cache between runs, see
+// inputsManifestKey.
+type inputsManifest struct {
+	Fingerprint string            `json:"fingerprint"`
+	Files       map[string]string `json:"files"`
+}
+
+// inputsManifestKey returns the bolt cache key of the inputs manifest. The
+// cache is shared by all projects, so the key is bound to the module root.
+func (m *Module[T]) inputsManifestKey() string {
+	return "gogh-inputs-manifest:" + m.root
+}
+
+// UpToDate checks if inputs declared with WithInputs have not changed since
+// the last Render and files rendered then were not changed as well. It is
+// meant to skip expensive generation entirely:
+//
+//	if ok, err := mod.UpToDate(); err != nil {
+//	    return err
+//	} else if ok {
+//	    return nil
+//	}
+//
+// It always returns false if there are no inputs declared.
+func (m *Module[T]) UpToDate() (bool, error) {
+	if len(m.inputs) == 0 {
+		return false, nil
+	}
+
+	fp, err := m.inputsFingerprint()
+	if err != nil {
+		return false, errors.Wrap(err, "compute inputs fingerprint")
+	}
+
+	manifest, err := m.inputsManifest()
+	if err != nil {
+		return false, err
+	}
+	if manifest.Fingerprint != fp || len(manifest.Files) == 0 {
+		return false, nil
+	}
+
+	for file, hash := range manifest.Files {
+		if fileHash(filepath.Join(m.root, file)) != hash {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// inputsFingerprint computes a hash of declared inputs, generator binary
+// and its arguments. It is computed the same way AutogenInputs does.
+func (m *Module[T]) inputsFingerprint() (string, error) {
+	if m.fingerprint != "" {
+		return m.fingerprint, nil
+	}
+
+	keys := generatorKeys(os.Args[1:])
+
+	var files []string
+	for _, input := range m.inputs {
+		keys = append(keys, input.kind, input.value, input.extra)
+
+		switch input.kind {
+		case "glob":
+			pattern := input.value
+			if !filepath.IsAbs(pattern) {
+				pattern = filepath.Join(m.root, pattern)
+			}
+			matches, err := filepath.Glob(pattern)
+			if err != nil {
+				return "", errors.Wrapf(err, "match input pattern %s", input.value)
+			}
+			files = append(files, matches...)
+
+		case "package":
+			var pkg struct {
+				Dir        string
+				GoFiles    []string
+				CgoFiles   []string
+				EmbedFiles []string
+			}
+			if err := jsonexec.Run(&pkg, "go", "list", "-json", input.value); err != nil {
+				return "", errors.Wrapf(err, "look for input package %s", input.value)
+			}
+			for _, names := range [][]string{pkg.GoFiles, pkg.CgoFiles, pkg.EmbedFiles} {
+				for _, name := range names {
+					files = append(files, filepath.Join(pkg.Dir, name))
+				}
+			}
+		}
+	}
+
+	hash, err := inputsHash(m.root, keys, files)
+	if err != nil {
+		return "", err
+	}
+
+	m.fingerprint = hash
+	return hash, nil
+}
+
+func (m *Module[T]) inputsManifest() (inputsManifest, error) {
+	var res inputsManifest
+	data, err := m.getValueFromBolt(m.inputsManifestKey())
+	if err != nil {
+		return res, errors.Wrap(err, "get inputs manifest")
+	}
+	if data == "" {
+		return res, nil
+	}
+
+	if err := json.Unmarshal([]byte(data), &res); err != nil {
+		return res, errors.Wrap(err, "decode inputs manifest")
+	}
+
+	return res, nil
+}
+
+// renderTracker skips rendering of files that have not changed since
+// the last run and collects hashes of rendered ones.
+type renderTracker struct {
+	root     string
+	enabled  bool
+	previous inputsManifest
+	current  inputsManifest
+}
+
+func (m *Module[T]) renderTracker() (*renderTracker, error) {
+	res := &renderTracker{
+		root: m.root,
+		current: inputsManifest{
+			Files: map[string]string{},
+		},
+	}
+	if len(m.inputs) == 0 {
+		return res, nil
+	}
+
+	fp, err := m.inputsFingerprint()
+	if err != nil {
+		return nil, errors.Wrap(err, "compute inputs fingerprint")
+	}
+	previous, err := m.inputsManifest()
+	if err != nil {
+		return nil, err
+	}
+
+	res.enabled = true
+	res.previous = previous
+	res.current.Fingerprint = fp
+	return res, nil
+}
+
+// skip checks if the file at the given path relative to the module root
+// is up to date.
+func (t *renderTracker) skip(relpath string) bool {
+	if !t.enabled || t.previous.Fingerprint != t.current.Fingerprint {
+		return false
+	}
+
+	hash, ok := t.previous.Files[relpath]
+	if !ok || fileHash(filepath.Join(t.root, relpath)) != hash {
+		return false
+	}
+
+	t.current.Files[relpath] = hash
+	return true
+}
+
+// done records the rendered file.
+func (t *renderTracker) done(relpath string) {
+	if !t.enabled {
+		return
+	}
+
+	if hash := fileHash(filepath.Join(t.root, relpath)); hash != "" {
+		t.current.Files[relpath] = hash
+	}
+}
+
+func (m *Module[T]) saveRenderTracker(t *renderTracker) error {
+	if !t.enabled {
+		return nil
+	}
+
+	data, err := json.Marshal(t.current)
+	if err != nil {
+		return errors.Wrap(err, "encode inputs manifest")
+	}
+	if err := m.putValueToBold(m.inputsManifestKey(), string(data)); err != nil {
+		return errors.Wrap(err, "save inputs manifest")
+	}
+
+	return nil
+}
+
+// fileHash returns sha256 of the file content or an empty string
+// if it cannot be read.
+func fileHash(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
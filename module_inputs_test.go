@@ -0,0 +1,109 @@
+package gogh
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestModuleInputs(t *testing.T) {
+	p := newTestPackage(t)
+	m := p.mod
+
+	if err := os.Mkdir(filepath.Join(m.root, "inputs"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	config := filepath.Join(m.root, "inputs", "config.yaml")
+	if err := os.WriteFile(config, []byte("a: 1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	m.inputs = []Input{
+		InputGlob("inputs/*.yaml"),
+		InputPackage("strings"),
+		InputKey("mode", "fast"),
+	}
+
+	render := func(content string) string {
+		t.Helper()
+
+		m.raws = map[string]*RawRenderer{}
+		r := p.Raw("query.sql")
+		r.R(content)
+		if err := m.render(); err != nil {
+			t.Fatal(err)
+		}
+
+		data, err := os.ReadFile(r.fullname)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+	upToDate := func() bool {
+		t.Helper()
+
+		ok, err := m.UpToDate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ok
+	}
+
+	if upToDate() {
+		t.Error("nothing has been rendered yet")
+	}
+	if got := render("SELECT 1;"); got != "SELECT 1;\n" {
+		t.Errorf("unexpected output %q", got)
+	}
+	if !upToDate() {
+		t.Error("inputs have not changed")
+	}
+	if got := render("SELECT 2;"); got != "SELECT 1;\n" {
+		t.Errorf("file must not be rendered while inputs are the same, got %q", got)
+	}
+
+	// Changed file is rendered again.
+	if err := os.WriteFile(filepath.Join(m.root, "query.sql"), []byte("manual\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if upToDate() {
+		t.Error("rendered file has been changed")
+	}
+	if got := render("SELECT 2;"); got != "SELECT 2;\n" {
+		t.Errorf("changed file must be rendered again, got %q", got)
+	}
+
+	// Files are rendered again when inputs change.
+	if err := os.WriteFile(config, []byte("a: 2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	m.fingerprint = ""
+	if upToDate() {
+		t.Error("inputs have been changed")
+	}
+	if got := render("SELECT 3;"); got != "SELECT 3;\n" {
+		t.Errorf("file must be rendered after inputs change, got %q", got)
+	}
+
+	// Manifests of modules sharing the cache do not mix up.
+	other := newTestPackage(t).mod
+	other.bolt = m.bolt
+	other.inputs = m.inputs
+	for _, name := range []string{"query.sql", "inputs/config.yaml"} {
+		data, err := os.ReadFile(filepath.Join(m.root, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(other.root, name)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(other.root, name), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if ok, err := other.UpToDate(); err != nil {
+		t.Fatal(err)
+	} else if ok {
+		t.Error("other module has not been rendered yet")
+	}
+}
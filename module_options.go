@@ -31,3 +31,75 @@ func WithProtoRegistry[T Importer](registry *protoast.Registry) ModuleOption[T]
 		m.registry = registry
 	}
 }
+
+// WithProtoResolvers sets resolvers of proto files sources. Sources are
+// needed to get field labels protoast does not expose, like proto3 optional
+// which makes protoc-gen-go generate pointers. These are normally the same
+// resolvers the registry given with WithProtoRegistry was built with.
+func WithProtoResolvers[T Importer](resolvers ...protoast.PathResolver) ModuleOption[T] {
+	return func(_ hiddenType, m *Module[T]) {
+		m.protoResolvers = resolvers
+	}
+}
+
+// WithProtoGoPackage sets Go package of types generated for the given proto
+// file, just like protoc M option does:
+//
+//	WithProtoGoPackage[T]("foo/bar.proto", "example.com/foo/bar;barpb")
+//
+// The package name after ";" is optional. The mapping takes precedence over
+// go_package option of the file.
+func WithProtoGoPackage[T Importer](file, goPackage string) ModuleOption[T] {
+	return func(_ hiddenType, m *Module[T]) {
+		if m.protoMapping == nil {
+			m.protoMapping = map[string]string{}
+		}
+		m.protoMapping[file] = goPackage
+	}
+}
+
+// WithDocWidth sets the width doc comments rendered with Doc are wrapped at.
+// It is 80 by default.
+func WithDocWidth[T Importer](width int) ModuleOption[T] {
+	return func(_ hiddenType, m *Module[T]) {
+		m.docWidth = width
+	}
+}
+
+// WithErrorWrapper sets the error wrapping style used by ReturnWrap and IfErr.
+// ErrorWrapFmt is used by default.
+func WithErrorWrapper[T Importer](wrapper ErrorWrapper) ModuleOption[T] {
+	return func(_ hiddenType, m *Module[T]) {
+		m.errWrapper = wrapper
+	}
+}
+
+// WithLicense sets license header of all Go files rendered. Files with
+// License or SPDX option set override it.
+func WithLicense[T Importer](text string) ModuleOption[T] {
+	return func(_ hiddenType, m *Module[T]) {
+		m.license = text
+	}
+}
+
+// WithDefaultRendererOptions sets options applied to every Go and raw
+// renderer of the module. Autogen, AutogenInputs, License, SPDX, GoBuild,
+// WithFormatter and WithCommentStyle passed for a file replace the default
+// of the same kind. Values of WithValue and WithValues are merged, file ones
+// take precedence. Other options are applied after defaults. Without opts
+// the file out of a default.
+func WithDefaultRendererOptions[T Importer](opts ...RendererOption) ModuleOption[T] {
+	return func(_ hiddenType, m *Module[T]) {
+		m.defaultOpts = append(m.defaultOpts, opts...)
+	}
+}
+
+// WithInputs declares generator inputs. Render skips files that were
+// rendered with the same inputs before and have not been changed since.
+// The generator binary and its arguments are inputs as well. See also
+// Module.UpToDate.
+func WithInputs[T Importer](inputs ...Input) ModuleOption[T] {
+	return func(_ hiddenType, m *Module[T]) {
+		m.inputs = append(m.inputs, inputs...)
+	}
+}
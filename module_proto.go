@@ -0,0 +1,202 @@
+package gogh
+
+import (
+	"bytes"
+	"go/token"
+	"os"
+	"path"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/emicklei/proto"
+	"github.com/sirkon/errors"
+	"github.com/sirkon/message"
+	"github.com/sirkon/protoast/v2/past"
+)
+
+// ProtoGoPackage returns the path and the name of the Go package where
+// protoc-gen-go puts types generated for the given node.
+//
+// The package is taken from WithProtoGoPackage mappings, then from the
+// list of well-known protobuf files and then from go_package option of
+// the node file. The package name is the one given explicitly in the
+// "path;name" form or the last path element otherwise.
+func (m *Module[T]) ProtoGoPackage(node past.Node) (pkgpath string, name string, err error) {
+	if m.registry == nil {
+		return "", "", errors.New("proto registry is not set, use WithProtoRegistry")
+	}
+
+	for n := range m.registry.NodeHierarchy(node) {
+		file, ok := n.(*past.File)
+		if !ok {
+			continue
+		}
+
+		pkg, err := m.protoGoPackage(file)
+		if err != nil {
+			return "", "", errors.Wrapf(err, "resolve go package of %s", file.Name())
+		}
+
+		return pkg.path, pkg.name, nil
+	}
+
+	return "", "", errors.New("orphan node without a file in its hierarchy ties")
+}
+
+// protoGoPackage Go package of protoc-gen-go generated code of a proto file.
+type protoGoPackage struct {
+	path string
+	name string
+}
+
+func (m *Module[T]) protoGoPackage(file *past.File) (protoGoPackage, error) {
+	if res, ok := m.protoPkgs[file.Name()]; ok {
+		return res, nil
+	}
+
+	var res protoGoPackage
+	if source, ok := m.protoMapping[file.Name()]; ok {
+		res.path, res.name, _ = strings.Cut(source, ";")
+	} else if pkgpath, ok := protoWellKnownPackages[file.Name()]; ok {
+		res.path = pkgpath
+	} else {
+		option := m.registry.OptionNamed(file, "go_package")
+		if option == nil {
+			return res, errors.New("missing go_package option, set the package with WithProtoGoPackage")
+		}
+		pkg := m.registry.GoPackageOption(option)
+		if pkg == nil {
+			return res, errors.New("invalid go_package option")
+		}
+		res.path = pkg.Path
+		res.name = pkg.Name
+	}
+
+	if res.path == "" {
+		return res, errors.New("empty go package path")
+	}
+	if res.name == "" {
+		res.name = protoGoPackageName(res.path)
+	}
+	if err := validatePackageName(res.name); err != nil {
+		return res, errors.Wrapf(err, "validate go package name '%s'", res.name)
+	}
+
+	if m.protoPkgs == nil {
+		m.protoPkgs = map[string]protoGoPackage{}
+	}
+	m.protoPkgs[file.Name()] = res
+
+	// Generated packages may not exist yet, so their names cannot be found
+	// with go list.
+	if err := m.putValueToBold(res.path, res.name); err != nil {
+		message.Warning(errors.Wrap(err, "failed to save package name into the cold hash"))
+	}
+
+	return res, nil
+}
+
+// protoGoPackageName computes the package name protoc-gen-go uses for the
+// package path without an explicit name.
+func protoGoPackageName(pkgpath string) string {
+	name := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return '_'
+	}, path.Base(pkgpath))
+
+	if r, _ := utf8.DecodeRuneInString(name); !unicode.IsLetter(r) || token.Lookup(name).IsKeyword() {
+		return "_" + name
+	}
+
+	return name
+}
+
+// protoPos position of a node within its proto file.
+type protoPos struct {
+	line   int
+	column int
+}
+
+// protoSource facts about a proto file past does not expose.
+type protoSource struct {
+	// labeled positions of fields having optional or required label.
+	labeled map[protoPos]struct{}
+	// fields names of message fields, including oneofs, in their
+	// declaration order by message positions.
+	fields map[protoPos][]string
+}
+
+// protoSource returns facts about the proto file taken from its source
+// found with WithProtoResolvers resolvers.
+func (m *Module[T]) protoSource(file string) (*protoSource, error) {
+	if res, ok := m.protoSources[file]; ok {
+		return res, nil
+	}
+
+	if len(m.protoResolvers) == 0 {
+		return nil, errors.New("proto sources are not set, use WithProtoResolvers")
+	}
+
+	var source string
+	for _, resolver := range m.protoResolvers {
+		name, err := resolver.Resolve(file)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+
+			return nil, errors.Wrap(err, "resolve proto file path with "+resolver.String())
+		}
+
+		source = name
+		break
+	}
+	if source == "" {
+		return nil, errors.Newf("proto file %s not found", file)
+	}
+
+	data, err := os.ReadFile(source)
+	if err != nil {
+		return nil, errors.Wrap(err, "read proto file")
+	}
+	parsed, err := proto.NewParser(bytes.NewReader(data)).Parse()
+	if err != nil {
+		return nil, errors.Wrap(err, "parse proto file")
+	}
+
+	res := &protoSource{
+		labeled: map[protoPos]struct{}{},
+		fields:  map[protoPos][]string{},
+	}
+	proto.Walk(
+		parsed,
+		proto.WithNormalField(func(f *proto.NormalField) {
+			if f.Optional || f.Required {
+				res.labeled[protoPos{line: f.Position.Line, column: f.Position.Column}] = struct{}{}
+			}
+		}),
+		proto.WithMessage(func(msg *proto.Message) {
+			pos := protoPos{line: msg.Position.Line, column: msg.Position.Column}
+			for _, e := range msg.Elements {
+				switch v := e.(type) {
+				case *proto.NormalField:
+					res.fields[pos] = append(res.fields[pos], v.Name)
+				case *proto.MapField:
+					res.fields[pos] = append(res.fields[pos], v.Name)
+				case *proto.Oneof:
+					res.fields[pos] = append(res.fields[pos], v.Name)
+				}
+			}
+		}),
+	)
+
+	if m.protoSources == nil {
+		m.protoSources = map[string]*protoSource{}
+	}
+	m.protoSources[file] = res
+
+	return res, nil
+}
@@ -0,0 +1,91 @@
+package gogh
+
+import (
+	"testing"
+)
+
+func TestModuleProtoGoPackage(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		mapping  map[string]string
+		wantPath string
+		wantName string
+		wantErr  bool
+	}{
+		{
+			name:     "explicit-name",
+			file:     "example/v1/example.proto",
+			wantPath: "example.com/proto/example/v1",
+			wantName: "examplev1",
+		},
+		{
+			name:     "implicit-name",
+			file:     "example/v1/plain.proto",
+			wantPath: "example.com/proto/plain-api",
+			wantName: "plain_api",
+		},
+		{
+			name:     "well-known",
+			file:     "google/protobuf/timestamp.proto",
+			wantPath: "google.golang.org/protobuf/types/known/timestamppb",
+			wantName: "timestamppb",
+		},
+		{
+			name:    "missing",
+			file:    "example/v1/nopkg.proto",
+			wantErr: true,
+		},
+		{
+			name: "mapped",
+			file: "example/v1/nopkg.proto",
+			mapping: map[string]string{
+				"example/v1/nopkg.proto": "example.com/proto/nopkg;nopkgpb",
+			},
+			wantPath: "example.com/proto/nopkg",
+			wantName: "nopkgpb",
+		},
+		{
+			name: "mapped-over-option",
+			file: "example/v1/plain.proto",
+			mapping: map[string]string{
+				"example/v1/plain.proto": "example.com/proto/plain",
+			},
+			wantPath: "example.com/proto/plain",
+			wantName: "plain",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestPackage(t)
+			testProtos(t, p)
+			for file, pkg := range tt.mapping {
+				WithProtoGoPackage[*Imports](file, pkg)(hiddenType{}, p.mod)
+			}
+
+			file, err := p.mod.registry.Proto(tt.file)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			pkgpath, name, err := p.mod.ProtoGoPackage(file)
+			if err != nil {
+				if !tt.wantErr {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+			if tt.wantErr {
+				t.Fatal("error expected")
+			}
+
+			if pkgpath != tt.wantPath || name != tt.wantName {
+				t.Errorf("unexpected package %s;%s, want %s;%s", pkgpath, name, tt.wantPath, tt.wantName)
+			}
+			if got := p.Go("x.go").imports.Imports().getPkgName(pkgpath); got != tt.wantName {
+				t.Errorf("package name %s must be known to imports, got %s", tt.wantName, got)
+			}
+		})
+	}
+}
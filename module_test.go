@@ -0,0 +1,123 @@
+package gogh
+
+import (
+	"bytes"
+	"go/ast"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/boltdb/bolt"
+	"github.com/sirkon/protoast/v2"
+	"github.com/sirkon/protoast/v2/past"
+)
+
+// newTestPackage creates a package "test" of a module "example.com/test" placed
+// in the temporary directory. It bypasses the New call which requires a module
+// in the current directory.
+func newTestPackage(t *testing.T) *Package[*Imports] {
+	t.Helper()
+
+	root := t.TempDir()
+	db, err := bolt.Open(filepath.Join(root, "bolt.db"), 0600, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+
+	m := &Module[*Imports]{
+		name:   "example.com/test",
+		root:   root,
+		goroot: runtime.GOROOT(),
+		fmt:    GoFmt,
+		importer: func(r *Imports) *Imports {
+			return r
+		},
+		pkgs:       map[string]*Package[*Imports]{},
+		raws:       map[string]*RawRenderer{},
+		pkgcache:   map[string]string{},
+		bolt:       db,
+		goghBucket: []byte("gogh-projects"),
+	}
+
+	// Packages produced by typesOf cannot be found with go list.
+	if err := m.putValueToBold("example.com/source", "source"); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := m.Root("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return p
+}
+
+// testProtos sets up protobuf registry of the package module with files
+// from testdata/proto and returns example/v1/example.proto.
+func testProtos(t *testing.T, p *Package[*Imports]) *past.File {
+	t.Helper()
+
+	resolvers, err := protoast.Resolvers().WithRoot("testdata/proto").Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	registry, err := protoast.NewRegistry(resolvers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file, err := registry.Proto("example/v1/example.proto")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.mod.registry = registry
+	p.mod.protoResolvers = resolvers
+
+	return file
+}
+
+// renderedCode returns formatted code rendered with r without a header.
+func renderedCode(t *testing.T, r *GoRenderer[*Imports]) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	for _, block := range r.blocksmgr.Collect() {
+		_, _ = io.Copy(&buf, bytes.NewReader(block.Bytes()))
+	}
+
+	res, err := format.Source(buf.Bytes())
+	if err != nil {
+		t.Fatalf("format rendered code: %s\n%s", err, buf.String())
+	}
+
+	return string(res)
+}
+
+// typesOf type checks the given source and returns its package.
+func typesOf(t *testing.T, src string) *types.Package {
+	t.Helper()
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "source.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := types.Config{
+		Importer: importer.Default(),
+	}
+	pkg, err := cfg.Check("example.com/source", fset, []*ast.File{file}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pkg
+}
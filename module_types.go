@@ -0,0 +1,41 @@
+package gogh
+
+import (
+	"go/importer"
+	"go/token"
+	"go/types"
+
+	"github.com/sirkon/errors"
+)
+
+// typesPackage returns type information of the package with the given path.
+// Packages are loaded from sources once and are cached, as well as errors
+// of their loading.
+func (m *Module[T]) typesPackage(pkgpath string) (*types.Package, error) {
+	if v, ok := m.typesCache[pkgpath]; ok {
+		return v.pkg, v.err
+	}
+
+	if m.typesCache == nil {
+		m.typesCache = map[string]typesCacheItem{}
+	}
+	if m.typesImporter == nil {
+		m.typesImporter = importer.ForCompiler(token.NewFileSet(), "source", nil).(types.ImporterFrom)
+	}
+
+	pkg, err := m.typesImporter.ImportFrom(pkgpath, m.root, 0)
+	if err != nil {
+		err = errors.Wrap(err, "load package type information").Str("pkg-path", pkgpath)
+	}
+	m.typesCache[pkgpath] = typesCacheItem{
+		pkg: pkg,
+		err: err,
+	}
+
+	return pkg, err
+}
+
+type typesCacheItem struct {
+	pkg *types.Package
+	err error
+}
@@ -23,6 +23,11 @@ type Package[T Importer] struct {
 
 	rs  map[string]*GoRenderer[T]
 	frs map[string]map[*GoRenderer[T]]struct{}
+
+	// protoConvs base names of rendered Go↔protobuf converters.
+	protoConvs map[string]string
+
+	defaultOpts []RendererOption
 }
 
 // Package creates "subpackage" of the current package
@@ -43,7 +48,6 @@ func (p *Package[T]) Go(name string, opts ...RendererOption) (res *GoRenderer[T]
 	res = &GoRenderer[T]{
 		name:      name,
 		pkg:       p,
-		options:   opts,
 		vals:      newEmptyValScope(),
 		blocksmgr: blocks.New(),
 		uniqs:     map[string]struct{}{},
@@ -103,6 +107,7 @@ func (p *Package[T]) Go(name string, opts ...RendererOption) (res *GoRenderer[T]
 		corrector: nil,
 	}
 	res.imports = p.mod.importer(imports)
+	res.options = rendererValues(res, rendererOptions(p.defaultRendererOptions(), opts))
 	p.rs[name] = res
 
 	return res
@@ -231,7 +236,19 @@ func (p *Package[T]) Void() *GoRenderer[T] {
 
 // Raw creates new or reuse existing plain text file renderer.
 func (p *Package[T]) Raw(name string, opts ...RendererOption) *RawRenderer {
-	return p.mod.Raw(path.Join(p.rel, name))
+	return p.mod.raw(path.Join(p.rel, name), p.defaultRendererOptions(), opts)
+}
+
+// SetDefaultRendererOptions sets options applied to every Go and raw
+// renderer of the package created after this call. They go after default
+// options of the module and are merged with them the same way file options
+// are, see WithDefaultRendererOptions.
+func (p *Package[T]) SetDefaultRendererOptions(opts ...RendererOption) {
+	p.defaultOpts = opts
+}
+
+func (p *Package[T]) defaultRendererOptions() []RendererOption {
+	return rendererOptions(p.mod.defaultOpts, p.defaultOpts)
 }
 
 // Path returns package path
@@ -55,11 +55,13 @@ type GoRenderer[T Importer] struct {
 	options []RendererOption
 
 	linebuf             bytes.Buffer
-	cmt                 *bytes.Buffer
+	hdr                 fileHeader
+	format              Formatter
 	vals                *valScope
 	blocksmgr           *blocks.Manager
 	uniqs               map[string]struct{}
 	uniqTags            map[any]string
+	fn                  *GoFuncRenderer[T]
 	preImport           map[string]struct{}
 	reuse               bool
 	reuseFirstImportPos int
@@ -345,6 +347,7 @@ func (r *GoRenderer[T]) Scope() (res *GoRenderer[T]) {
 		blocksmgr: r.blocksmgr,
 		uniqs:     maps.Clone(r.uniqs),
 		uniqTags:  maps.Clone(r.uniqTags),
+		fn:        r.fn,
 	}
 }
 
@@ -398,6 +401,7 @@ func (r *GoRenderer[T]) Z() (res *GoRenderer[T]) {
 		blocksmgr: r.blocksmgr.Insert().Prev(),
 		uniqs:     r.uniqs,
 		uniqTags:  r.uniqTags,
+		fn:        r.fn,
 	}
 
 	return res
@@ -422,32 +426,7 @@ func (r *GoRenderer[T]) T() *GoRenderer[T] {
 func (r *GoRenderer[T]) Type(t types.Type) string {
 	switch v := t.(type) {
 	case *types.Named:
-		typ := v.Obj()
-		pkg := typ.Pkg()
-		if pkg == nil || pkg.Path() == r.pkg.Path() {
-			return typ.Name()
-		}
-		alias := r.imports.Add(pkg.Path()).push()
-
-		var res strings.Builder
-		res.WriteString(alias)
-		res.WriteByte('.')
-		res.WriteString(typ.Name())
-		if v.TypeParams().Len() != 0 {
-			res.WriteByte('[')
-			for i := 0; i < v.TypeParams().Len(); i++ {
-				if i > 0 {
-					res.WriteString(", ")
-				}
-
-				res.WriteString(v.TypeParams().At(i).Obj().Name())
-				res.WriteByte(' ')
-				res.WriteString(r.Type(v.TypeParams().At(i).Obj().Type()))
-			}
-			res.WriteByte(']')
-		}
-
-		return res.String()
+		return r.typeName(v.Obj(), v.TypeParams(), v.TypeArgs())
 	case *types.Pointer:
 		return "*" + r.Type(v.Elem())
 	case *types.Slice:
@@ -458,37 +437,25 @@ func (r *GoRenderer[T]) Type(t types.Type) string {
 		// заморачиваться
 		return v.String()
 	case *types.Struct:
-		// аналогично предыдущему пункту
-		return v.String()
+		fields := make([]string, 0, v.NumFields())
+		for i := 0; i < v.NumFields(); i++ {
+			f := v.Field(i)
+			field := r.Type(f.Type())
+			if !f.Embedded() {
+				field = f.Name() + " " + field
+			}
+			if tag := v.Tag(i); tag != "" {
+				field += " " + strconv.Quote(tag)
+			}
+			fields = append(fields, field)
+		}
+		return "struct{" + strings.Join(fields, "; ") + "}"
 	case *types.Basic:
 		return v.String()
 	case *types.Alias:
-		typ := v.Obj()
-		pkg := typ.Pkg()
-		if pkg == nil || pkg.Path() == r.pkg.Path() {
-			return typ.Name()
-		}
-		alias := r.imports.Add(pkg.Path()).push()
-
-		var res strings.Builder
-		res.WriteString(alias)
-		res.WriteByte('.')
-		res.WriteString(typ.Name())
-		if v.TypeParams().Len() != 0 {
-			res.WriteByte('[')
-			for i := 0; i < v.TypeParams().Len(); i++ {
-				if i > 0 {
-					res.WriteString(", ")
-				}
-
-				res.WriteString(v.TypeParams().At(i).Obj().Name())
-				res.WriteByte(' ')
-				res.WriteString(r.Type(v.TypeParams().At(i).Obj().Type()))
-			}
-			res.WriteByte(']')
-		}
-
-		return res.String()
+		return r.typeName(v.Obj(), v.TypeParams(), v.TypeArgs())
+	case *types.TypeParam:
+		return v.Obj().Name()
 	case *types.Map:
 		return fmt.Sprintf("map[%s]%s", r.Type(v.Key()), r.Type(v.Elem()))
 	case *types.Signature:
@@ -527,6 +494,67 @@ func (r *GoRenderer[T]) Type(t types.Type) string {
 	}
 }
 
+// typeString renders a type given as a string, fmt.Stringer,
+// types.Type or past.Type. Strings are formatted.
+func (r *GoRenderer[T]) typeString(typ any) string {
+	switch v := typ.(type) {
+	case string:
+		return r.S(v)
+	case types.Type:
+		return r.Type(v)
+	case past.Type:
+		return r.Proto(v).Impl()
+	case fmt.Stringer:
+		return r.S(v.String())
+	default:
+		panic(errors.Newf(
+			"type can be string|fmt.Stringer|%T|%T, got %T",
+			types.Type(nil),
+			past.Type(nil),
+			typ,
+		))
+	}
+}
+
+// typeName renders qualified name of a named type or an alias.
+// Instantiated generics are rendered with their type arguments,
+// generic types themselves with their type parameters.
+func (r *GoRenderer[T]) typeName(typ *types.TypeName, params *types.TypeParamList, args *types.TypeList) string {
+	var res strings.Builder
+	pkg := typ.Pkg()
+	if pkg != nil && pkg.Path() != r.pkg.Path() {
+		res.WriteString(r.imports.Add(pkg.Path()).push())
+		res.WriteByte('.')
+	}
+	res.WriteString(typ.Name())
+
+	switch {
+	case args.Len() != 0:
+		res.WriteByte('[')
+		for i := 0; i < args.Len(); i++ {
+			if i > 0 {
+				res.WriteString(", ")
+			}
+			res.WriteString(r.Type(args.At(i)))
+		}
+		res.WriteByte(']')
+	case params.Len() != 0 && pkg != nil && pkg.Path() != r.pkg.Path():
+		res.WriteByte('[')
+		for i := 0; i < params.Len(); i++ {
+			if i > 0 {
+				res.WriteString(", ")
+			}
+
+			res.WriteString(params.At(i).Obj().Name())
+			res.WriteByte(' ')
+			res.WriteString(r.Type(params.At(i).Constraint()))
+		}
+		res.WriteByte(']')
+	}
+
+	return res.String()
+}
+
 // PkgObject renders fully qualified object name used with the referenced package.
 // The reference can be done with one of:
 //   - *types.Named.
@@ -599,7 +627,7 @@ func (r *GoRenderer[T]) Proto(t past.Type) ProtocType {
 		case *past.Message:
 			res := r.Proto(p)
 			res.pointer = false
-			res.selector += "_" + Proto(v.Name())
+			res.selector += "_" + protocGoName(v.Name())
 			return res
 		case *past.File:
 			var alias string
@@ -608,7 +636,7 @@ func (r *GoRenderer[T]) Proto(t past.Type) ProtocType {
 			}
 			return ProtocType{
 				source:   alias,
-				selector: Proto(v.Name()),
+				selector: protocGoName(v.Name()),
 			}
 		default:
 			panic(errors.Newf(
@@ -617,7 +645,16 @@ func (r *GoRenderer[T]) Proto(t past.Type) ProtocType {
 			))
 		}
 	case *past.EnumValue:
-		res := r.Proto(r.protoRegistry().NodeParent(v).(*past.Enum))
+		// Values of nested enums are prefixed with their message name
+		// rather than the enum name.
+		enum := r.protoRegistry().NodeParent(v).(*past.Enum)
+		var res ProtocType
+		if msg, ok := r.protoRegistry().NodeParent(enum).(*past.Message); ok {
+			res = r.Proto(msg)
+			res.pointer = false
+		} else {
+			res = r.Proto(enum)
+		}
 		res.selector += "_" + v.Name()
 		return res
 	case *past.Repeated:
@@ -629,22 +666,6 @@ func (r *GoRenderer[T]) Proto(t past.Type) ProtocType {
 		if file == nil {
 			panic("no file for message " + v.Name())
 		}
-		// если это гугловые врапперы, то для них своя процедура
-		if file.Name() == "google/protobuf/wrappers.proto" {
-			// ура, ето врапперы!
-			r.imports.Add("google.golang.org/protobuf/Protos/known/wrapperspb").Ref("wrappers")
-			switch v.Name() {
-			case "DoubleValue", "FloatValue", "Int64Value", "UInt64Value",
-				"Int32Value", "UInt32Value", "BoolValue", "StringValue", "BytesValue":
-			default:
-				panic(errors.Newf("unsupported google wrapper %s.%s", file.Name(), v.Name()))
-			}
-			return ProtocType{
-				pointer:  true,
-				source:   r.S("$wrappers"),
-				selector: Proto(v.Name()),
-			}
-		}
 		parent := r.protoRegistry().NodeParent(v)
 		switch p := parent.(type) {
 		case *past.File:
@@ -655,17 +676,16 @@ func (r *GoRenderer[T]) Proto(t past.Type) ProtocType {
 			return ProtocType{
 				pointer:  true,
 				source:   alias,
-				selector: Proto(v.Name()),
+				selector: protocGoName(v.Name()),
 			}
 		default:
 			res := r.Proto(p.(past.Type))
-			res.selector += "_" + Proto(v.Name())
+			res.selector += "_" + protocGoName(v.Name())
+			return res
 		}
 	default:
 		panic(errors.Newf("Proto %T is not supported", v))
 	}
-
-	panic("unreachable")
 }
 
 // path returns generated file path
@@ -678,9 +698,19 @@ func (r *GoRenderer[T]) localPath() string {
 	return path.Join(r.pkg.Path(), r.name)
 }
 
+func (r *GoRenderer[T]) root() string {
+	return r.pkg.mod.root
+}
+
 func (r *GoRenderer[T]) render() error {
 	data := &bytes.Buffer{}
 
+	body := &bytes.Buffer{}
+	for _, block := range r.blocksmgr.Collect() {
+		_, _ = io.Copy(body, block)
+	}
+	src := r.imports.Imports().pruneDocImports(body.Bytes(), r.reuse)
+
 	if !r.reuse {
 		for _, option := range r.options {
 			if !option(r) {
@@ -688,10 +718,10 @@ func (r *GoRenderer[T]) render() error {
 			}
 		}
 
-		if r.cmt != nil {
-			_, _ = io.Copy(data, r.cmt)
-			data.WriteString("\n")
+		if len(r.hdr.license) == 0 && r.pkg.mod.license != "" {
+			License(r.pkg.mod.license)(r)
 		}
+		data.Write(r.hdr.render(CommentSlashes))
 
 		data.WriteString("package ")
 		data.WriteString(r.pkg.name)
@@ -715,9 +745,7 @@ func (r *GoRenderer[T]) render() error {
 		}
 	}
 
-	for _, block := range r.blocksmgr.Collect() {
-		_, _ = io.Copy(data, block)
-	}
+	data.Write(src)
 
 	if r.reuse && len(r.imports.Imports().pkgs) > 0 {
 		var tmp bytes.Buffer
@@ -756,7 +784,11 @@ func (r *GoRenderer[T]) render() error {
 		_, _ = tmp.WriteTo(data)
 	}
 
-	res, err := r.pkg.mod.fmt(data.Bytes())
+	format := r.pkg.mod.fmt
+	if r.format != nil {
+		format = r.format
+	}
+	res, err := format(data.Bytes())
 	if err != nil {
 		message.Error(err)
 		return errors.New("failed to format rendered file")
@@ -782,22 +814,26 @@ func (r *GoRenderer[T]) renderCtx() *format.ContextBuilder {
 	return res
 }
 
-func (r *GoRenderer[T]) comment() *bytes.Buffer {
-	if r.cmt == nil {
-		r.cmt = &bytes.Buffer{}
-	}
+func (r *GoRenderer[T]) header() *fileHeader {
+	return &r.hdr
+}
 
-	return r.cmt
+func (r *GoRenderer[T]) setCommentStyle(style CommentStyle) {
+	if style != CommentSlashes {
+		panic(errors.New("go files only support // comments"))
+	}
 }
 
 func (r *GoRenderer[T]) setVals(vals map[string]any) {
-	for name := range vals {
-		if r.vals.CheckScope(name) {
-			panic(errors.Newf("attempt to '%s' into different value", name))
-		}
+	for name, value := range vals {
+		r.Let(name, value)
 	}
 }
 
+func (r *GoRenderer[T]) setFormatter(f Formatter) {
+	r.format = f
+}
+
 func (r *GoRenderer[T]) newline() {
 	r.last().WriteByte('\n')
 }
@@ -812,24 +848,16 @@ func (r *GoRenderer[T]) isInSamePackage(t past.Node) bool {
 	return reference == r.pkg.Path()
 }
 
-// TODO probably a cache would make it a bit better.
+// protocTypePkgPath возвращает путь пакета сгенерированного protoc-gen-go типа.
+// Паникует, если его не удалось определить, так как Proto и производные от него
+// методы не возвращают ошибок. Проверить заранее можно с помощью Module.ProtoGoPackage.
 func (r *GoRenderer[T]) protocTypePkgPath(t past.Node) string {
-	registry := r.protoRegistry()
-	for node := range registry.NodeHierarchy(t) {
-		f, ok := node.(*past.File)
-		if !ok {
-			continue
-		}
-
-		option := registry.OptionNamed(f, "go_package")
-		pkg := registry.GoPackageOption(option)
-		if pkg == nil {
-			panic("missing go_package option")
-		}
-		return pkg.Path
+	pkgpath, _, err := r.pkg.mod.ProtoGoPackage(t)
+	if err != nil {
+		panic(errors.Wrap(err, "get go package of the generated type"))
 	}
 
-	panic("orphan node without a file in its hierarchy ties")
+	return pkgpath
 }
 
 func (r *GoRenderer[T]) handlePanic() {
@@ -0,0 +1,248 @@
+package gogh
+
+import (
+	"go/types"
+	"strings"
+	"unicode"
+
+	"github.com/sirkon/errors"
+)
+
+// Decorator returns a renderer of a wrapper type over the given interface.
+// The wrapper embeds an implementation of the interface and forwards
+// every method call to it, calling hooks set with Before and After
+// around the call.
+//
+// The iface must be a named interface type (or an alias of it), since
+// it is to be embedded. Generic interfaces must be instantiated.
+//
+// Usage example:
+//
+//	r.Imports().Add("log/slog").Ref("slog")
+//	r.Decorator("LoggingStorage", iface).
+//	    Field("log", "*$slog.Logger").
+//	    After(func(r *GoRenderer[T], m *DecoratedMethod) {
+//	        r.L(`$rcvr.log.Info("$0 called")`, m.Method.Name())
+//	    }).
+//	    Render()
+//
+// Produces something like
+//
+//	type LoggingStorage struct {
+//	    storage.Storage
+//	    log *slog.Logger
+//	}
+//
+//	func NewLoggingStorage(next storage.Storage, log *slog.Logger) *LoggingStorage {
+//	    return &LoggingStorage{
+//	        Storage: next,
+//	        log:     log,
+//	    }
+//	}
+//
+//	func (l *LoggingStorage) Get(ctx context.Context, key string) (res []byte, err error) {
+//	    res, err = l.Storage.Get(ctx, key)
+//	    l.log.Info("Get called")
+//	    return res, err
+//	}
+func (r *GoRenderer[T]) Decorator(name string, iface types.Type) *GoDecoratorRenderer[T] {
+	checkName("decorator", name)
+
+	var obj *types.TypeName
+	switch v := iface.(type) {
+	case *types.Named:
+		obj = v.Obj()
+		if v.TypeParams().Len() != v.TypeArgs().Len() {
+			panic(errors.Newf("generic interface %s must be instantiated to be decorated", obj.Name()))
+		}
+	case *types.Alias:
+		obj = v.Obj()
+	default:
+		panic(errors.Newf("decorated type must be a named interface, got %s", iface))
+	}
+
+	return &GoDecoratorRenderer[T]{
+		r:     r,
+		name:  name,
+		field: obj.Name(),
+		iface: iface,
+		it:    interfaceOf(iface),
+	}
+}
+
+type (
+	// GoDecoratorRenderer renders a decorator over an interface.
+	GoDecoratorRenderer[T Importer] struct {
+		r      *GoRenderer[T]
+		name   string
+		field  string
+		iface  types.Type
+		it     *types.Interface
+		fields [][2]any
+		before func(r *GoRenderer[T], m *DecoratedMethod)
+		after  func(r *GoRenderer[T], m *DecoratedMethod)
+	}
+
+	// DecoratedMethod describes a method being decorated for hooks.
+	// Names of parameters and results are the ones used in the
+	// rendered method, they are guaranteed to be valid and unique.
+	DecoratedMethod struct {
+		Method  *types.Func
+		Params  []*types.Var
+		Results []*types.Var
+	}
+)
+
+// Field adds a field into the decorator type. It will be a parameter
+// of the constructor too. The typ is a string, fmt.Stringer, types.Type
+// or past.Type.
+func (d *GoDecoratorRenderer[T]) Field(name string, typ any) *GoDecoratorRenderer[T] {
+	checkName("decorator field", name)
+	d.fields = append(d.fields, [2]any{name, typ})
+	return d
+}
+
+// Before sets a hook to be called before the forwarded call. Method
+// parameters are available, results are not set yet. The renderer
+// given to the hook has $rcvr set to the receiver name and
+// $ReturnZeroValues preset.
+func (d *GoDecoratorRenderer[T]) Before(f func(r *GoRenderer[T], m *DecoratedMethod)) *GoDecoratorRenderer[T] {
+	d.before = f
+	return d
+}
+
+// After sets a hook to be called after the forwarded call. Both
+// parameters and results are available here. Results are named
+// and will be returned as is after the hook.
+func (d *GoDecoratorRenderer[T]) After(f func(r *GoRenderer[T], m *DecoratedMethod)) *GoDecoratorRenderer[T] {
+	d.after = f
+	return d
+}
+
+// Render renders decorator type, its constructor and methods.
+func (d *GoDecoratorRenderer[T]) Render() {
+	r := d.r.Scope()
+	r.Let("decorator", d.name)
+	r.Let("embedded", d.field)
+	r.Let("iface", d.iface)
+
+	r.L(`type $decorator struct {`)
+	r.L(`    $iface`)
+	for _, f := range d.fields {
+		r.L(`    $0 $1`, f[0], r.typeString(f[1]))
+	}
+	r.L(`}`)
+	r.N()
+
+	var params Params
+	names := r.Scope()
+	next := names.Uniq("next")
+	params.Add(next, r.S("$iface"))
+	for _, f := range d.fields {
+		params.Add(names.Uniq(f[0].(string)), r.typeString(f[1]))
+	}
+	r.L(`// New$decorator creates $decorator over the given implementation.`)
+	r.F("New" + d.name)(params).Returns("*$decorator").Body(func(r *GoRenderer[T]) {
+		r.L(`return &$decorator{`)
+		r.L(`    $embedded: $0,`, next)
+		for i, f := range d.fields {
+			r.L(`    $0: $1,`, f[0], params.data[i+1][0])
+		}
+		r.L(`}`)
+	})
+
+	for i := 0; i < d.it.NumMethods(); i++ {
+		r.N()
+		d.renderMethod(r, d.it.Method(i))
+	}
+}
+
+func (d *GoDecoratorRenderer[T]) renderMethod(r *GoRenderer[T], method *types.Func) {
+	sig := method.Type().(*types.Signature)
+
+	// Names are to be computed before the method rendering
+	// since the receiver name depends on parameter names.
+	names := r.Scope()
+	m := &DecoratedMethod{
+		Method: method,
+	}
+	var params Params
+	var args []string
+	for i := 0; i < sig.Params().Len(); i++ {
+		p := sig.Params().At(i)
+		name := p.Name()
+		if name == "" || name == "_" {
+			name = "arg"
+		}
+		name = names.Uniq(name)
+
+		m.Params = append(m.Params, types.NewVar(p.Pos(), p.Pkg(), name, p.Type()))
+		if sig.Variadic() && i == sig.Params().Len()-1 {
+			params.Add(name, "..."+r.Type(p.Type().(*types.Slice).Elem()))
+			args = append(args, name+"...")
+			continue
+		}
+		params.Add(name, r.Type(p.Type()))
+		args = append(args, name)
+	}
+
+	var results []any
+	var rets []string
+	for i := 0; i < sig.Results().Len(); i++ {
+		p := sig.Results().At(i)
+		name := p.Name()
+		if name == "" || name == "_" {
+			name = "res"
+			if i == sig.Results().Len()-1 && isErrorType(p.Type()) {
+				name = "err"
+			}
+		}
+		name = names.Uniq(name)
+
+		v := types.NewVar(p.Pos(), p.Pkg(), name, p.Type())
+		m.Results = append(m.Results, v)
+		results = append(results, v)
+		rets = append(rets, name)
+	}
+
+	rcvr := names.Uniq(receiverName(d.name))
+	r.M(rcvr, "*$decorator")(method.Name())(params).Returns(results...).Body(func(r *GoRenderer[T]) {
+		r.Let("rcvr", rcvr)
+
+		if d.before != nil {
+			d.before(r, m)
+		}
+
+		call := r.S(`$rcvr.$embedded.$0($1)`, method.Name(), strings.Join(args, ", "))
+		if len(rets) > 0 {
+			r.L(`$0 = $1`, strings.Join(rets, ", "), call)
+		} else {
+			r.R(call)
+		}
+
+		if d.after != nil {
+			d.after(r, m)
+		}
+
+		if len(rets) > 0 {
+			r.L(`return $0`, strings.Join(rets, ", "))
+		}
+	})
+}
+
+func receiverName(typeName string) string {
+	for _, c := range typeName {
+		return string(unicode.ToLower(c))
+	}
+
+	return "x"
+}
+
+func isErrorType(t types.Type) bool {
+	v, ok := t.Underlying().(*types.Interface)
+	if !ok {
+		return false
+	}
+
+	return isErrorCompatibleInterface(v)
+}
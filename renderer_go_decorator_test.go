@@ -0,0 +1,77 @@
+package gogh
+
+import (
+	"testing"
+)
+
+func TestGoRendererDecorator(t *testing.T) {
+	src := typesOf(t, `package source
+
+import "context"
+
+type Storage interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Put(context.Context, string, []byte) error
+	Keys(prefixes ...string) (keys []string)
+	Reset()
+}
+`)
+
+	r := newTestPackage(t).Go("decorator.go")
+	r.Imports().Add("log").Ref("log")
+	r.Decorator("LoggingStorage", src.Scope().Lookup("Storage").Type()).
+		Field("logger", "*$log.Logger").
+		Before(func(r *GoRenderer[*Imports], m *DecoratedMethod) {
+			r.L(`$rcvr.logger.Println("$0 started")`, m.Method.Name())
+		}).
+		After(func(r *GoRenderer[*Imports], m *DecoratedMethod) {
+			if len(m.Results) == 0 {
+				return
+			}
+			r.L(`$rcvr.logger.Println("$0 finished", $1)`, m.Method.Name(), m.Results[len(m.Results)-1].Name())
+		}).
+		Render()
+
+	const want = `type LoggingStorage struct {
+	source.Storage
+	logger *log.Logger
+}
+
+// NewLoggingStorage creates LoggingStorage over the given implementation.
+func NewLoggingStorage(next source.Storage, logger *log.Logger) *LoggingStorage {
+	return &LoggingStorage{
+		Storage: next,
+		logger:  logger,
+	}
+}
+
+func (l *LoggingStorage) Get(ctx context.Context, key string) (res []byte, err error) {
+	l.logger.Println("Get started")
+	res, err = l.Storage.Get(ctx, key)
+	l.logger.Println("Get finished", err)
+	return res, err
+}
+
+func (l *LoggingStorage) Keys(prefixes ...string) (keys []string) {
+	l.logger.Println("Keys started")
+	keys = l.Storage.Keys(prefixes...)
+	l.logger.Println("Keys finished", keys)
+	return keys
+}
+
+func (l *LoggingStorage) Put(arg context.Context, arg2 string, arg3 []byte) (err error) {
+	l.logger.Println("Put started")
+	err = l.Storage.Put(arg, arg2, arg3)
+	l.logger.Println("Put finished", err)
+	return err
+}
+
+func (l *LoggingStorage) Reset() {
+	l.logger.Println("Reset started")
+	l.Storage.Reset()
+}
+`
+	if got := renderedCode(t, r); got != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,282 @@
+package gogh
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// defaultDocWidth is the doc comment width used when WithDocWidth was not set.
+const defaultDocWidth = 80
+
+// Doc renders a doc comment. The text is formatted the same way L does
+// and then is wrapped to fit the doc width of the module, see WithDocWidth.
+//
+// Go doc syntax is supported:
+//   - Paragraphs are separated with empty lines, lines of a paragraph are
+//     joined and rewrapped.
+//   - List items start with -, *, +, • or a number followed by . or ),
+//     they are wrapped with respect to their indentation.
+//   - Indented lines out of lists are code blocks and are kept as is.
+//   - Headings starting with "# " are kept as is.
+//   - Doc links like [example.com/pkg.Name] register the import of the
+//     package and are shortened to [pkg.Name] using its name or alias.
+//     Links to the package being rendered are shortened to [Name]. Imports
+//     of packages the file does not use otherwise are dropped at rendering,
+//     their links are turned back into full path ones.
+//
+// Usage example:
+//
+//	r.Doc(`$0 returns a list of users matching the filter. See [$1] for details.`, name, "example.com/users.Filter")
+func (r *GoRenderer[T]) Doc(text string, a ...any) {
+	for _, line := range r.docLines(text, a...) {
+		r.R(docCommentLine(line))
+	}
+}
+
+func (r *GoRenderer[T]) docLines(text string, a ...any) []string {
+	width := r.pkg.mod.docWidth
+	if width <= 0 {
+		width = defaultDocWidth
+	}
+
+	return wrapDoc(r.S(text, a...), width-len("// "), func(link string) string {
+		// Package path ends at the first dot after the last slash.
+		start := strings.LastIndexByte(link, '/') + 1
+		end := strings.IndexByte(link[start:], '.')
+		if end < 0 {
+			return link
+		}
+		end += start
+
+		pkgpath := link[:end]
+		if pkgpath == r.pkg.Path() {
+			return link[end+1:]
+		}
+
+		// A single word can be a name of an imported package as well, it
+		// is only treated as a path if it was imported.
+		imports := r.imports.Imports()
+		if start == 0 && !imports.imported(pkgpath) {
+			return link
+		}
+
+		return imports.docLink(pkgpath) + link[end:]
+	})
+}
+
+// imported checks if the package has been imported in the file.
+func (i *Imports) imported(pkgpath string) bool {
+	i.pushImports()
+	_, ok := i.pkgs[pkgpath]
+	return ok
+}
+
+// docLink registers the import of a package referred to with a doc link
+// and returns its name or alias.
+func (i *Imports) docLink(pkgpath string) string {
+	if !i.imported(pkgpath) {
+		if i.docs == nil {
+			i.docs = map[string]struct{}{}
+		}
+		i.docs[pkgpath] = struct{}{}
+	}
+
+	return i.Add(pkgpath).Alias()
+}
+
+// pruneDocImports drops imports registered with doc links which the source
+// does not use and turns these links back into full path ones, as an
+// unused import does not compile. The src is a whole file if full is set
+// and file declarations otherwise.
+func (i *Imports) pruneDocImports(src []byte, full bool) []byte {
+	if len(i.docs) == 0 {
+		return src
+	}
+
+	file := src
+	if !full {
+		file = append([]byte("package p\n\n"), src...)
+	}
+	f, err := parser.ParseFile(token.NewFileSet(), "", file, parser.ParseComments)
+	if err != nil {
+		// Leave it to the formatter to report.
+		return src
+	}
+
+	used := map[string]struct{}{}
+	ast.Inspect(f, func(n ast.Node) bool {
+		if v, ok := n.(*ast.SelectorExpr); ok {
+			if x, ok := v.X.(*ast.Ident); ok && x.Obj == nil {
+				used[x.Name] = struct{}{}
+			}
+		}
+		return true
+	})
+
+	lines := bytes.Split(src, []byte("\n"))
+	for pkgpath := range i.docs {
+		alias, ok := i.pkgs[pkgpath]
+		if !ok {
+			continue
+		}
+		if _, ok := used[alias]; ok {
+			continue
+		}
+
+		delete(i.pkgs, pkgpath)
+		for j, line := range lines {
+			if bytes.HasPrefix(bytes.TrimSpace(line), []byte("//")) {
+				lines[j] = bytes.ReplaceAll(line, []byte("["+alias+"."), []byte("["+pkgpath+"."))
+			}
+		}
+	}
+
+	return bytes.Join(lines, []byte("\n"))
+}
+
+func docCommentLine(line string) string {
+	if line == "" || line[0] == '\t' {
+		return "//" + line
+	}
+
+	return "// " + line
+}
+
+var (
+	docListItem = regexp.MustCompile(`^\s*([-*+•]|\d+[.)])\s+`)
+	docLink     = regexp.MustCompile(`\[([^\[\]\s]+)\]`)
+)
+
+// wrapDoc splits doc comment text into lines of the given width at most,
+// except ones having words that do not fit. The link function maps doc
+// links text.
+func wrapDoc(text string, width int, link func(string) string) []string {
+	var res []string
+	var para []string
+	var item string
+	var inList bool
+	var inCode bool
+	var codeIndent string
+
+	sep := func() {
+		if len(res) > 0 && res[len(res)-1] != "" {
+			res = append(res, "")
+		}
+	}
+	flush := func() {
+		switch {
+		case item != "":
+			words := strings.Fields(docLinks(strings.Join(para, " "), link))
+			lines := wrapWords(words, width-len(item)-3)
+			res = append(res, "  "+item+" "+lines[0])
+			for _, line := range lines[1:] {
+				res = append(res, strings.Repeat(" ", len(item)+3)+line)
+			}
+		case len(para) > 0:
+			sep()
+			words := strings.Fields(docLinks(strings.Join(para, " "), link))
+			res = append(res, wrapWords(words, width)...)
+		}
+		para = para[:0]
+		item = ""
+	}
+
+	for _, line := range strings.Split(strings.Trim(text, "\n"), "\n") {
+		line = strings.TrimRightFunc(line, unicode.IsSpace)
+		indented := line != "" && (line[0] == ' ' || line[0] == '\t')
+
+		switch {
+		case line == "":
+			flush()
+			inList = false
+			if inCode {
+				res = append(res, "")
+			}
+		case docListItem.MatchString(line):
+			flush()
+			if !inList {
+				sep()
+			}
+			inList = true
+			inCode = false
+			loc := docListItem.FindStringSubmatchIndex(line)
+			item = line[loc[2]:loc[3]]
+			para = append(para, line[loc[1]:])
+		case indented && inList:
+			para = append(para, strings.TrimSpace(line))
+		case indented:
+			flush()
+			if !inCode {
+				sep()
+				codeIndent = line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+			}
+			inCode = true
+			if !strings.HasPrefix(line, codeIndent) {
+				line = strings.TrimLeft(line, " \t")
+			}
+			res = append(res, "\t"+strings.TrimPrefix(line, codeIndent))
+		case strings.HasPrefix(line, "# "):
+			flush()
+			sep()
+			res = append(res, line, "")
+			inCode = false
+		default:
+			if inList || inCode {
+				flush()
+				sep()
+				inList = false
+				inCode = false
+			}
+			para = append(para, strings.TrimSpace(line))
+		}
+	}
+	flush()
+
+	for len(res) > 0 && res[len(res)-1] == "" {
+		res = res[:len(res)-1]
+	}
+
+	return res
+}
+
+func docLinks(text string, link func(string) string) string {
+	if link == nil {
+		return text
+	}
+
+	return docLink.ReplaceAllStringFunc(text, func(s string) string {
+		return "[" + link(s[1:len(s)-1]) + "]"
+	})
+}
+
+func wrapWords(words []string, width int) []string {
+	if len(words) == 0 {
+		return []string{""}
+	}
+
+	var res []string
+	var line strings.Builder
+	var length int
+	for _, word := range words {
+		wordLength := utf8.RuneCountInString(word)
+		if length > 0 && length+1+wordLength > width {
+			res = append(res, line.String())
+			line.Reset()
+			length = 0
+		}
+		if length > 0 {
+			line.WriteByte(' ')
+			length++
+		}
+		line.WriteString(word)
+		length += wordLength
+	}
+
+	return append(res, line.String())
+}
@@ -0,0 +1,156 @@
+package gogh
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestWrapDoc(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		width int
+		want  string
+	}{
+		{
+			name:  "paragraphs",
+			text:  "Lorem ipsum dolor sit amet,\nconsectetur adipiscing elit.\n\nSed do eiusmod.",
+			width: 20,
+			want: `Lorem ipsum dolor
+sit amet,
+consectetur
+adipiscing elit.
+
+Sed do eiusmod.`,
+		},
+		{
+			name:  "long-word",
+			text:  "a https://example.com/very/long/link b",
+			width: 10,
+			want: `a
+https://example.com/very/long/link
+b`,
+		},
+		{
+			name:  "list",
+			text:  "Items:\n- first item is long\n  enough to wrap\n* second\n1. third",
+			width: 17,
+			want: `Items:
+
+  - first item is
+    long enough
+    to wrap
+  * second
+  1. third`,
+		},
+		{
+			name:  "code",
+			text:  "Example:\n\n    if x {\n        return\n    }\n\nDone.",
+			width: 80,
+			want:  "Example:\n\n\tif x {\n\t    return\n\t}\n\nDone.",
+		},
+		{
+			name:  "heading",
+			text:  "# Usage\nCall it.",
+			width: 80,
+			want: `# Usage
+
+Call it.`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := strings.Join(wrapDoc(tt.text, tt.width, nil), "\n")
+			if got != tt.want {
+				t.Errorf("unexpected output:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGoRendererDoc(t *testing.T) {
+	p := newTestPackage(t)
+	if err := p.mod.putValueToBold("example.com/other", "other"); err != nil {
+		t.Fatal(err)
+	}
+	r := p.Go("doc.go")
+	r.Imports().Add("strings").As("strs")
+	r.Let("strs", "strs")
+	r.Doc("$0 joins parts with [strings.Join]. See also [example.com/other.Type] and [example.com/test.Local].", "Join")
+	r.F("Join")("parts", "[]string").
+		Returns("string").
+		Body(func(r *GoRenderer[*Imports]) {
+			r.L(`return $strs.Join(parts, "")`)
+		})
+	r.N()
+	r.F("Split")("s", "string").
+		Doc("Split is a function with a quite long doc comment which is to be wrapped at the doc width.").
+		Doc("- second paragraph is a list").
+		Returns("[]string").
+		Body(func(r *GoRenderer[*Imports]) {
+			r.L(`return $strs.Fields(s)`)
+		})
+	r.N()
+	r.Doc("$0 is documented before [bytes.Buffer] gets imported.", "Buf")
+	r.Imports().Add("bytes").Ref("bytes")
+	r.L(`var Buf $bytes.Buffer`)
+
+	const want = `// Join joins parts with [strs.Join]. See also [other.Type] and [Local].
+func Join(parts []string) string {
+	return strs.Join(parts, "")
+}
+
+// Split is a function with a quite long doc comment which is to be wrapped at
+// the doc width.
+//
+//   - second paragraph is a list
+func Split(s string) []string {
+	return strs.Fields(s)
+}
+
+// Buf is documented before [bytes.Buffer] gets imported.
+var Buf bytes.Buffer
+`
+	if got := renderedCode(t, r); got != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestGoRendererDocImports(t *testing.T) {
+	p := newTestPackage(t)
+	for pkgpath, name := range map[string]string{
+		"example.com/used":   "used",
+		"example.com/unused": "unused",
+	} {
+		if err := p.mod.putValueToBold(pkgpath, name); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r := p.Go("doc.go")
+	r.Doc("$0 is [example.com/used.Type], see also [example.com/unused.Type].", "Value")
+	r.L(`var Value $0.Type`, r.Imports().Add("example.com/used").Alias())
+	if err := r.render(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(r.path())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const want = `package test
+
+import (
+	"example.com/used"
+)
+
+// Value is [used.Type], see also [example.com/unused.Type].
+var Value used.Type
+`
+	if string(data) != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", data, want)
+	}
+}
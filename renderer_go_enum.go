@@ -0,0 +1,341 @@
+package gogh
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/types"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/sirkon/errors"
+	"github.com/sirkon/protoast/v2/past"
+)
+
+// Enum returns a renderer of an enumeration: a named type with the
+// given base type and a set of iota constants of it. The base is a
+// string, fmt.Stringer, types.Type or past.Type and must be an
+// integer type.
+//
+// Constant names are made of the type name and value labels with the
+// naming function, which is Public by default:
+//
+//	r.Enum("Color", "uint8").
+//	    Value("red").
+//	    Value("dark-blue").
+//	    WithString().
+//	    WithParse().
+//	    Render()
+//
+// Produces
+//
+//	type Color uint8
+//
+//	const (
+//	    ColorRed Color = iota
+//	    ColorDarkBlue
+//	)
+//
+//	// String to implement fmt.Stringer.
+//	func (c Color) String() string {
+//	    switch c {
+//	    case ColorRed:
+//	        return "red"
+//	    case ColorDarkBlue:
+//	        return "dark-blue"
+//	    default:
+//	        return fmt.Sprintf("Color(%d)", uint8(c))
+//	    }
+//	}
+//
+//	// ParseColor parses Color from its text representation.
+//	func ParseColor(s string) (Color, error) {
+//	    …
+//	}
+//
+// It panics if the base is known not to be an integer type. Named types
+// given as strings cannot be checked and are accepted as is.
+func (r *GoRenderer[T]) Enum(name string, base any) *GoEnumRenderer[T] {
+	checkName("enum", name)
+
+	typ := r.typeString(base)
+	if !enumBaseIsInteger(base, typ) {
+		panic(errors.Newf("enum %s base type must be an integer type, got %s", name, typ))
+	}
+
+	return &GoEnumRenderer[T]{
+		r:      r,
+		name:   name,
+		base:   typ,
+		naming: Public,
+		names:  r.Scope(),
+		labels: map[string]struct{}{},
+	}
+}
+
+type (
+	// GoEnumRenderer renders an enumeration type, its constants and methods.
+	GoEnumRenderer[T Importer] struct {
+		r      *GoRenderer[T]
+		name   string
+		base   string
+		naming func(head string, parts ...string) string
+		names  *GoRenderer[T]
+		labels map[string]struct{}
+		doc    []string
+		values []enumValue
+
+		str   bool
+		parse bool
+		text  bool
+		valid bool
+		list  bool
+	}
+
+	enumValue struct {
+		label string
+		name  string
+	}
+)
+
+// Naming sets a function to build constant names with. It is called
+// with the type name and words of a value label. Public, Proto and
+// alike functions from this package are meant to be used here.
+// It must be set before any Value call.
+func (e *GoEnumRenderer[T]) Naming(f func(head string, parts ...string) string) *GoEnumRenderer[T] {
+	if len(e.values) > 0 {
+		panic(errors.New("enum naming must be set before values"))
+	}
+
+	e.naming = f
+	return e
+}
+
+// Doc adds a paragraph of the enum type doc comment. The text is
+// formatted and wrapped the same way GoRenderer.Doc does.
+func (e *GoEnumRenderer[T]) Doc(text string, a ...any) *GoEnumRenderer[T] {
+	if len(e.doc) > 0 {
+		e.doc = append(e.doc, "")
+	}
+	e.doc = append(e.doc, e.r.docLines(text, a...)...)
+	return e
+}
+
+// Value adds a value with the given label. The label is a text
+// representation of the value used by String, Parse and text
+// marshaling methods.
+func (e *GoEnumRenderer[T]) Value(label string) *GoEnumRenderer[T] {
+	if _, ok := e.labels[label]; ok {
+		panic(errors.Newf("enum %s label '%s' has been defined already", e.name, label))
+	}
+	e.labels[label] = struct{}{}
+
+	words := strings.FieldsFunc(label, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) == 0 {
+		panic(errors.Newf("enum %s label '%s' must contain letters or digits", e.name, label))
+	}
+
+	name := e.naming(e.name, words...)
+	checkName("enum constant", name)
+	if e.names.Uniq(name) != name {
+		panic(errors.Newf("enum %s constant %s for label '%s' clashes with the previous one", e.name, name, label))
+	}
+
+	e.values = append(e.values, enumValue{
+		label: label,
+		name:  name,
+	})
+	return e
+}
+
+// WithString enables String method rendering.
+func (e *GoEnumRenderer[T]) WithString() *GoEnumRenderer[T] {
+	e.str = true
+	return e
+}
+
+// WithParse enables Parse<Name>(string) function rendering.
+func (e *GoEnumRenderer[T]) WithParse() *GoEnumRenderer[T] {
+	e.parse = true
+	return e
+}
+
+// WithText enables MarshalText and UnmarshalText methods rendering.
+// Parse<Name> function is rendered too as UnmarshalText relies on it.
+func (e *GoEnumRenderer[T]) WithText() *GoEnumRenderer[T] {
+	e.text = true
+	e.parse = true
+	return e
+}
+
+// WithIsValid enables IsValid method rendering.
+func (e *GoEnumRenderer[T]) WithIsValid() *GoEnumRenderer[T] {
+	e.valid = true
+	return e
+}
+
+// WithValues enables <Name>Values function rendering, which returns
+// the list of all values.
+func (e *GoEnumRenderer[T]) WithValues() *GoEnumRenderer[T] {
+	e.list = true
+	return e
+}
+
+// Render renders the enum.
+func (e *GoEnumRenderer[T]) Render() {
+	if len(e.values) == 0 {
+		panic(errors.Newf("enum %s must have values", e.name))
+	}
+
+	r := e.r.Scope()
+	r.Let("enum", e.name)
+	r.Let("base", e.base)
+	r.Let("rcvr", r.Uniq(receiverName(e.name)))
+
+	for _, line := range e.doc {
+		r.R(docCommentLine(line))
+	}
+	r.L(`type $enum $base`)
+	r.N()
+	r.L(`const (`)
+	for i, v := range e.values {
+		if i == 0 {
+			r.L(`    $0 $enum = iota`, v.name)
+			continue
+		}
+		r.L(`    $0`, v.name)
+	}
+	r.L(`)`)
+
+	if e.str {
+		r.N()
+		e.renderString(r)
+	}
+	if e.parse {
+		r.N()
+		e.renderParse(r)
+	}
+	if e.text {
+		r.N()
+		e.renderText(r)
+	}
+	if e.valid {
+		r.N()
+		r.L(`// IsValid checks if the value is one of defined $enum constants.`)
+		r.L(`func ($rcvr $enum) IsValid() bool {`)
+		r.L(`    return $rcvr >= $0 && $rcvr <= $1`, e.values[0].name, e.values[len(e.values)-1].name)
+		r.L(`}`)
+	}
+	if e.list {
+		r.N()
+		r.L(`// ${enum}Values returns all $enum values.`)
+		r.L(`func ${enum}Values() []$enum {`)
+		r.L(`    return []$enum{`)
+		for _, v := range e.values {
+			r.L(`        $0,`, v.name)
+		}
+		r.L(`    }`)
+		r.L(`}`)
+	}
+}
+
+func (e *GoEnumRenderer[T]) renderString(r *GoRenderer[T]) {
+	r.Imports().Add("fmt").Ref("fmt")
+
+	r.L(`// String to implement fmt.Stringer.`)
+	r.L(`func ($rcvr $enum) String() string {`)
+	r.L(`    switch $rcvr {`)
+	for _, v := range e.values {
+		r.L(`    case $0:`, v.name)
+		r.L(`        return $0`, strconv.Quote(v.label))
+	}
+	r.L(`    default:`)
+	r.L(`        return $fmt.Sprintf("$enum(%d)", $base($rcvr))`)
+	r.L(`    }`)
+	r.L(`}`)
+}
+
+func (e *GoEnumRenderer[T]) renderParse(r *GoRenderer[T]) {
+	r.Imports().Add("fmt").Ref("fmt")
+
+	r.L(`// Parse$enum parses $enum from its text representation.`)
+	r.L(`func Parse$enum(s string) ($enum, error) {`)
+	r.L(`    switch s {`)
+	for _, v := range e.values {
+		r.L(`    case $0:`, strconv.Quote(v.label))
+		r.L(`        return $0, nil`, v.name)
+	}
+	r.L(`    default:`)
+	r.L(`        return 0, $fmt.Errorf("invalid $enum value %q", s)`)
+	r.L(`    }`)
+	r.L(`}`)
+}
+
+func (e *GoEnumRenderer[T]) renderText(r *GoRenderer[T]) {
+	r.Imports().Add("fmt").Ref("fmt")
+
+	r.L(`// MarshalText to implement encoding.TextMarshaler.`)
+	r.L(`func ($rcvr $enum) MarshalText() ([]byte, error) {`)
+	r.L(`    switch $rcvr {`)
+	for _, v := range e.values {
+		r.L(`    case $0:`, v.name)
+		r.L(`        return []byte($0), nil`, strconv.Quote(v.label))
+	}
+	r.L(`    default:`)
+	r.L(`        return nil, $fmt.Errorf("invalid $enum value %d", $base($rcvr))`)
+	r.L(`    }`)
+	r.L(`}`)
+	r.N()
+	r.L(`// UnmarshalText to implement encoding.TextUnmarshaler.`)
+	r.L(`func ($rcvr *$enum) UnmarshalText(text []byte) error {`)
+	r.L(`    @v, err := Parse$enum(string(text))`)
+	r.L(`    if err != nil {`)
+	r.L(`        return err`)
+	r.L(`    }`)
+	r.N()
+	r.L(`    *$rcvr = $v`)
+	r.L(`    return nil`)
+	r.L(`}`)
+}
+
+// enumBaseIsInteger checks if the enum base given either as is or rendered
+// as typ can be an integer type.
+func enumBaseIsInteger(base any, typ string) bool {
+	switch v := base.(type) {
+	case types.Type:
+		b, ok := v.Underlying().(*types.Basic)
+		return ok && b.Info()&types.IsInteger != 0
+	case past.Type:
+		switch v.(type) {
+		case *past.Int32, *past.Sint32, *past.Sfixed32,
+			*past.Int64, *past.Sint64, *past.Sfixed64,
+			*past.Uint32, *past.Fixed32,
+			*past.Uint64, *past.Fixed64,
+			*past.Enum:
+			return true
+		default:
+			return false
+		}
+	}
+
+	expr, err := parser.ParseExpr(typ)
+	if err != nil {
+		return false
+	}
+	switch v := expr.(type) {
+	case *ast.Ident:
+		obj, ok := types.Universe.Lookup(v.Name).(*types.TypeName)
+		if !ok {
+			// A type of the package.
+			return types.Universe.Lookup(v.Name) == nil
+		}
+		b, ok := obj.Type().Underlying().(*types.Basic)
+		return ok && b.Info()&types.IsInteger != 0
+	case *ast.SelectorExpr:
+		return true
+	default:
+		return false
+	}
+}
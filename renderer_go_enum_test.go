@@ -0,0 +1,170 @@
+package gogh
+
+import (
+	"go/types"
+	"testing"
+
+	"github.com/sirkon/protoast/v2/past"
+)
+
+func TestGoRendererEnum(t *testing.T) {
+	r := newTestPackage(t).Go("enum.go")
+	r.Enum("Value", types.Typ[types.Uint8]).
+		Doc("Value is a sample enum.").
+		Value("unknown").
+		Value("dark-red").
+		Value("HTTP code").
+		WithString().
+		WithText().
+		WithIsValid().
+		WithValues().
+		Render()
+	r.N()
+	r.Enum("Kind", "int").
+		Naming(Proto).
+		Value("first_one").
+		Render()
+
+	const want = `// Value is a sample enum.
+type Value uint8
+
+const (
+	ValueUnknown Value = iota
+	ValueDarkRed
+	ValueHTTPCode
+)
+
+// String to implement fmt.Stringer.
+func (v Value) String() string {
+	switch v {
+	case ValueUnknown:
+		return "unknown"
+	case ValueDarkRed:
+		return "dark-red"
+	case ValueHTTPCode:
+		return "HTTP code"
+	default:
+		return fmt.Sprintf("Value(%d)", uint8(v))
+	}
+}
+
+// ParseValue parses Value from its text representation.
+func ParseValue(s string) (Value, error) {
+	switch s {
+	case "unknown":
+		return ValueUnknown, nil
+	case "dark-red":
+		return ValueDarkRed, nil
+	case "HTTP code":
+		return ValueHTTPCode, nil
+	default:
+		return 0, fmt.Errorf("invalid Value value %q", s)
+	}
+}
+
+// MarshalText to implement encoding.TextMarshaler.
+func (v Value) MarshalText() ([]byte, error) {
+	switch v {
+	case ValueUnknown:
+		return []byte("unknown"), nil
+	case ValueDarkRed:
+		return []byte("dark-red"), nil
+	case ValueHTTPCode:
+		return []byte("HTTP code"), nil
+	default:
+		return nil, fmt.Errorf("invalid Value value %d", uint8(v))
+	}
+}
+
+// UnmarshalText to implement encoding.TextUnmarshaler.
+func (v *Value) UnmarshalText(text []byte) error {
+	v2, err := ParseValue(string(text))
+	if err != nil {
+		return err
+	}
+
+	*v = v2
+	return nil
+}
+
+// IsValid checks if the value is one of defined Value constants.
+func (v Value) IsValid() bool {
+	return v >= ValueUnknown && v <= ValueHTTPCode
+}
+
+// ValueValues returns all Value values.
+func ValueValues() []Value {
+	return []Value{
+		ValueUnknown,
+		ValueDarkRed,
+		ValueHTTPCode,
+	}
+}
+
+type Kind int
+
+const (
+	KindFirstOne Kind = iota
+)
+`
+	if got := renderedCode(t, r); got != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestGoRendererEnumBase(t *testing.T) {
+	tests := []struct {
+		name  string
+		base  any
+		valid bool
+	}{
+		{
+			name:  "builtin",
+			base:  "uint16",
+			valid: true,
+		},
+		{
+			name:  "named",
+			base:  "Code",
+			valid: true,
+		},
+		{
+			name:  "types-type",
+			base:  types.Typ[types.Int64],
+			valid: true,
+		},
+		{
+			name:  "proto",
+			base:  &past.Sfixed32{},
+			valid: true,
+		},
+		{
+			name: "string",
+			base: "string",
+		},
+		{
+			name: "float",
+			base: types.Typ[types.Float64],
+		},
+		{
+			name: "proto-string",
+			base: &past.String{},
+		},
+		{
+			name: "slice",
+			base: "[]int",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); (r == nil) != tt.valid {
+					t.Errorf("unexpected panic state: %v", r)
+				}
+			}()
+
+			newTestPackage(t).Go("enum.go").Enum("Kind", tt.base)
+		})
+	}
+}
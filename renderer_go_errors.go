@@ -0,0 +1,117 @@
+package gogh
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/sirkon/errors"
+)
+
+// ErrorWrapper renders an expression wrapping the err expression
+// with the given message. Imports needed are to be added with i.
+type ErrorWrapper func(i *Imports, err, msg string) string
+
+// ErrorWrapFmt wraps errors with fmt.Errorf and %w verb:
+//
+//	fmt.Errorf("msg: %w", err)
+//
+// This is the default wrapper.
+func ErrorWrapFmt(i *Imports, err, msg string) string {
+	msg = strings.ReplaceAll(msg, "%", "%%")
+	return i.Add("fmt").Alias() + ".Errorf(" + strconv.Quote(msg+": %w") + ", " + err + ")"
+}
+
+// ErrorWrapJoin wraps errors with errors.Join:
+//
+//	errors.Join(errors.New("msg"), err)
+func ErrorWrapJoin(i *Imports, err, msg string) string {
+	errs := i.Add("errors").Alias()
+	return errs + ".Join(" + errs + ".New(" + strconv.Quote(msg) + "), " + err + ")"
+}
+
+// ErrorWrapSirkon wraps errors with [github.com/sirkon/errors]:
+//
+//	errors.Wrap(err, "msg")
+func ErrorWrapSirkon(i *Imports, err, msg string) string {
+	return i.Add("github.com/sirkon/errors").Alias() + ".Wrap(" + err + ", " + strconv.Quote(msg) + ")"
+}
+
+// ReturnErr renders return statement of the current function with
+// its zero values and the given error expression. The expression is
+// formatted the same way L does.
+//
+//	r.ReturnErr(`$errs.New("not implemented")`)
+//
+// It panics if the zero values are unknown or if the last result
+// of the current function is not an error.
+func (r *GoRenderer[T]) ReturnErr(exprFormat string, a ...any) {
+	r.checkReturnErr()
+	r.L(`return $`+ReturnZeroValues+` `+exprFormat, a...)
+}
+
+// ReturnWrap renders return statement of the current function with
+// its zero values and the err expression wrapped with the given message.
+// The message is formatted the same way L does. Wrapping style is set with
+// WithErrorWrapper module option.
+//
+//	r.ReturnWrap("err", "read $0", name)
+//
+// Produces with the default wrapper
+//
+//	return nil, fmt.Errorf("read config: %w", err)
+func (r *GoRenderer[T]) ReturnWrap(err string, msgFormat string, a ...any) {
+	r.checkReturnErr()
+	r.L(`return $`+ReturnZeroValues+` $0`, r.wrapError(err, r.S(msgFormat, a...)))
+}
+
+// IfErr renders error check of a call returning just an error and a
+// return of the wrapped error when it is not nil. Both the call and the
+// message are formatted the same way L does with the given arguments.
+//
+//	r.IfErr(`$f.Close()`, "close file")
+//
+// Produces with the default wrapper
+//
+//	if err := f.Close(); err != nil {
+//	    return fmt.Errorf("close file: %w", err)
+//	}
+//
+// An empty call means an existing err variable is checked.
+func (r *GoRenderer[T]) IfErr(callFormat string, wrapMsg string, a ...any) {
+	r.checkReturnErr()
+
+	s := r.Scope()
+	if callFormat == "" {
+		s.L(`if err != nil {`)
+	} else {
+		s.L(`if err := `+callFormat+`; err != nil {`, a...)
+	}
+	s.ReturnWrap("err", wrapMsg, a...)
+	s.L(`}`)
+}
+
+func (r *GoRenderer[T]) checkReturnErr() {
+	if r.fn != nil {
+		results := r.fn.results
+		if len(results) == 0 || results[len(results)-1][1] != "error" {
+			panic(errors.Newf("%s %s must have error as its last result to return errors", r.fn.kind(), r.fn.name))
+		}
+	}
+
+	zeroes, ok := r.vals.Get(ReturnZeroValues)
+	if !ok {
+		panic(errors.Newf("zero values of return types are unknown, set them with SetReturnZeroValues"))
+	}
+	if v, ok := zeroes.(unknownZeroValues); ok {
+		panic(v.err)
+	}
+}
+
+func (r *GoRenderer[T]) wrapError(err, msg string) string {
+	wrapper := r.pkg.mod.errWrapper
+	if wrapper == nil {
+		wrapper = ErrorWrapFmt
+	}
+
+	return wrapper(r.imports.Imports(), err, msg)
+}
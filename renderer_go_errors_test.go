@@ -0,0 +1,127 @@
+package gogh
+
+import (
+	"testing"
+)
+
+func TestGoRendererErrors(t *testing.T) {
+	r := newTestPackage(t).Go("errors.go")
+	r.Imports().Add("os").Ref("os")
+	r.F("read")("name", "string").Returns("[]byte", "error", "").Body(func(r *GoRenderer[*Imports]) {
+		r.If(`$0 == ""`, "name").Then(func(r *GoRenderer[*Imports]) {
+			r.ReturnErr(`$0("empty name")`, "newError")
+		})
+		r.L(`f, err := $os.Open(name)`)
+		r.IfErr("", "open $0", "file")
+		r.IfErr("f.Close()", "close file")
+		r.ReturnWrap("err", "read 100%")
+	})
+	r.N()
+	r.F("close")("f", "*$os.File").Returns("error").Body(func(r *GoRenderer[*Imports]) {
+		r.IfErr("f.Close()", "close")
+		r.L(`return nil`)
+	})
+
+	const want = `func read(name string) ([]byte, error) {
+	if name == "" {
+		return nil, newError("empty name")
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("open file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return nil, fmt.Errorf("close file: %w", err)
+	}
+	return nil, fmt.Errorf("read 100%%: %w", err)
+}
+
+func close(f *os.File) error {
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("close: %w", err)
+	}
+	return nil
+}
+`
+	if got := renderedCode(t, r); got != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestGoRendererErrorsWrappers(t *testing.T) {
+	tests := []struct {
+		name    string
+		wrapper ErrorWrapper
+		want    string
+	}{
+		{
+			name:    "join",
+			wrapper: ErrorWrapJoin,
+			want:    `errors.Join(errors.New("do"), err)`,
+		},
+		{
+			name:    "sirkon",
+			wrapper: ErrorWrapSirkon,
+			want:    `errors.Wrap(err, "do")`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestPackage(t)
+			p.mod.errWrapper = tt.wrapper
+			r := p.Go("errors.go")
+			r.F("do")().Returns("error").Body(func(r *GoRenderer[*Imports]) {
+				r.IfErr("do()", "do")
+				r.L(`return nil`)
+			})
+
+			want := "func do() error {\n\tif err := do(); err != nil {\n\t\treturn " + tt.want + "\n\t}\n\treturn nil\n}\n"
+			if got := renderedCode(t, r); got != want {
+				t.Errorf("unexpected output:\n%s\nwant:\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestGoRendererErrorsPanics(t *testing.T) {
+	tests := []struct {
+		name string
+		f    func(r *GoRenderer[*Imports])
+	}{
+		{
+			name: "no-error-result",
+			f: func(r *GoRenderer[*Imports]) {
+				r.F("f")().Returns("int", "").Body(func(r *GoRenderer[*Imports]) {
+					r.ReturnErr("err")
+				})
+			},
+		},
+		{
+			name: "unknown-zeroes",
+			f: func(r *GoRenderer[*Imports]) {
+				r.F("f")().Returns("Unknown", "error", "").Body(func(r *GoRenderer[*Imports]) {
+					r.ReturnErr("err")
+				})
+			},
+		},
+		{
+			name: "out-of-function",
+			f: func(r *GoRenderer[*Imports]) {
+				r.ReturnErr("err")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("panic expected")
+				}
+			}()
+
+			tt.f(newTestPackage(t).Go("errors.go"))
+		})
+	}
+}
@@ -18,6 +18,8 @@ import (
 //   - a single instance of Params or *Params
 //   - a single instance of Commas or *Commas
 //   - a single instance of *types.Tuple, where names MUST NOT be empty.
+//   - a single instance of *types.Signature, its parameters are taken
+//     with respect to the variadic one.
 //   - a list of *types.Var, where names in each one MUST NOT be empty.
 //   - a list of (K₁, V₁, K₂, V₂, ..., Kₙ, Vₙ), where
 //     Kᵢ = (string | fmt.Stringer), except *types.Var even though it is fmt.Stringer.
@@ -39,6 +41,7 @@ func (r *GoRenderer[T]) F(name string) func(params ...any) *GoFuncRenderer[T] {
 			params:  nil,
 			results: nil,
 		}
+		res.r.fn = res
 		res.setFuncInfo(name, params...)
 
 		return res
@@ -74,6 +77,7 @@ func (r *GoRenderer[T]) M(rcvr ...any) func(name string) func(params ...any) *Go
 			params:  nil,
 			results: nil,
 		}
+		res.r.fn = res
 		res.setReceiverInfo(rcvr...)
 
 		return func(params ...any) *GoFuncRenderer[T] {
@@ -90,6 +94,8 @@ type (
 
 		rcvr    *string
 		name    string
+		doc     []string
+		tparams [][2]string
 		params  [][2]string
 		results [][2]string
 	}
@@ -100,6 +106,43 @@ type (
 	}
 )
 
+// Doc adds a paragraph of the function doc comment. The text is
+// formatted and wrapped the same way GoRenderer.Doc does.
+func (r *GoFuncRenderer[T]) Doc(text string, a ...any) *GoFuncRenderer[T] {
+	if len(r.doc) > 0 {
+		r.doc = append(r.doc, "")
+	}
+	r.doc = append(r.doc, r.r.docLines(text, a...)...)
+	return r
+}
+
+// TypeParams sets type parameters of the function. They are given
+// as (name, constraint) pairs, where the constraint is a string,
+// fmt.Stringer, types.Type or past.Type. Type parameters must be set
+// before Returns, since zero values of them are *new(T).
+//
+//	r.F("Map")("src", "[]S", "f", "func(S) D").TypeParams("S", "any", "D", "any").Returns("[]D")
+func (r *GoFuncRenderer[T]) TypeParams(params ...any) *GoFuncRenderer[T] {
+	if r.rcvr != nil {
+		panic(fmt.Sprintf("method %s cannot have type parameters", r.name))
+	}
+	if len(params)%2 != 0 {
+		panic(fmt.Sprintf("type parameters of %s must be (name, constraint) pairs", r.name))
+	}
+
+	for i := 0; i < len(params); i += 2 {
+		name, ok := params[i].(string)
+		if !ok {
+			panic(fmt.Sprintf("type parameter name must be string, got %T", params[i]))
+		}
+		checkName("type parameter", name)
+		r.takeVarName("type parameter", name)
+		r.tparams = append(r.tparams, [2]string{name, r.r.typeString(params[i+1])})
+	}
+
+	return r
+}
+
 // Returns sets up a return tuple of the function.
 //
 // We don't divide fmt.Stringer or string here, except stringers from
@@ -143,6 +186,15 @@ type (
 //   - Chans, maps, slices, pointers are supported too.
 //   - Error type is matched by its name, same guess as for builtins
 //     here.
+//
+// Types heuristics cannot deal with are resolved this way:
+//   - Type parameters set with TypeParams have *new(T) zero value.
+//   - Qualified names of imported packages and names of the current
+//     package are looked up in these packages sources.
+//   - Named results are used as their own zero values.
+//
+// If it is still impossible to compute zero values ReturnZeroValues is
+// set to fail formatting with an explanation.
 func (r *GoFuncRenderer[T]) Returns(results ...any) *GoFuncBodyRenderer[T] {
 	var zeroes []string
 
@@ -167,15 +219,14 @@ func (r *GoFuncRenderer[T]) Returns(results ...any) *GoFuncBodyRenderer[T] {
 		case *types.Tuple:
 			// We guess it is just an existing tuple from a source code
 			// that has to be correct, so let it be as is.
-			var zeroValues []string
 			for i := 0; i < v.Len(); i++ {
 				p := v.At(i)
 				r.takeVarName("argument", p.Name())
 				r.results = append(r.results, [2]string{p.Name(), r.r.Type(p.Type())})
-				zeroes = append(zeroValues, zeroValueOfTypesType(r.r, p.Type(), i == v.Len()-1))
+				zeroes = append(zeroes, zeroValueOfTypesType(r.r, p.Type(), i == v.Len()-1))
 			}
 		case string, fmt.Stringer:
-			r.results, _ = r.inPlaceSeq("argument", results...)
+			r.results, zeroes = r.inPlaceSeq("argument", results...)
 		default:
 			panic(fmt.Errorf("unsupported result literal type %T", results[0]))
 		}
@@ -184,20 +235,19 @@ func (r *GoFuncRenderer[T]) Returns(results ...any) *GoFuncBodyRenderer[T] {
 	}
 
 	// Check if all zero values were computed and save ReturnZeroValues
-	zeroesAreValid := true
-	for i, zero := range zeroes {
-		if zero == "" {
-			zeroesAreValid = false
-			break
-		}
-		if i == len(zeroes)-1 && zero == consts.ErrorTypeZeroSign {
-			zeroes[i] = ""
-			break
+	if len(zeroes) != len(r.results) {
+		zeroes = make([]string, len(r.results))
+	}
+	if err := r.resolveZeroes(zeroes); err != nil {
+		r.r.letSet(ReturnZeroValues, unknownZeroValues{err: err})
+		return &GoFuncBodyRenderer[T]{
+			r: r,
 		}
 	}
-	if zeroesAreValid && len(zeroes) == len(r.results) {
-		r.r.SetReturnZeroValues(zeroes...)
+	if len(zeroes) > 0 && zeroes[len(zeroes)-1] == consts.ErrorTypeZeroSign {
+		zeroes[len(zeroes)-1] = ""
 	}
+	r.r.SetReturnZeroValues(zeroes...)
 
 	return &GoFuncBodyRenderer[T]{
 		r: r,
@@ -215,6 +265,10 @@ func (r *GoFuncRenderer[T]) Body(f func(r *GoRenderer[T])) {
 
 // Body renders function body with the provided f function.
 func (r *GoFuncBodyRenderer[T]) Body(f func(r *GoRenderer[T])) {
+	for _, line := range r.r.doc {
+		r.r.r.R(docCommentLine(line))
+	}
+
 	var buf strings.Builder
 
 	buf.WriteString("func ")
@@ -224,6 +278,18 @@ func (r *GoFuncBodyRenderer[T]) Body(f func(r *GoRenderer[T])) {
 		buf.WriteString(") ")
 	}
 	buf.WriteString(r.r.r.S(r.r.name))
+	if len(r.r.tparams) > 0 {
+		buf.WriteByte('[')
+		for i, p := range r.r.tparams {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			buf.WriteString(p[0])
+			buf.WriteByte(' ')
+			buf.WriteString(p[1])
+		}
+		buf.WriteByte(']')
+	}
 	buf.WriteByte('(')
 	for i, p := range r.r.params {
 		if i > 0 {
@@ -289,6 +355,18 @@ func (r *GoFuncRenderer[T]) setFuncInfo(name string, params ...any) {
 				r.takeVarName("argument", p.Name())
 				r.params = append(r.params, [2]string{p.Name(), r.r.Type(p.Type())})
 			}
+		case *types.Signature:
+			// Same as for the tuple, just variadic parameters are taken into account.
+			ps := v.Params()
+			for i := 0; i < ps.Len(); i++ {
+				p := ps.At(i)
+				r.takeVarName("argument", p.Name())
+				if v.Variadic() && i == ps.Len()-1 {
+					r.params = append(r.params, [2]string{p.Name(), "..." + r.r.Type(p.Type().(*types.Slice).Elem())})
+					continue
+				}
+				r.params = append(r.params, [2]string{p.Name(), r.r.Type(p.Type())})
+			}
 		case string, fmt.Stringer:
 			r.params, _ = r.inPlaceSeq("argument", params...)
 		default:
@@ -312,12 +390,12 @@ func (r *GoFuncRenderer[T]) setReceiverInfo(rcvr ...any) {
 			r.takeVarName("receiver", v.Name())
 			rn = v.Name()
 			rt = r.r.Type(v.Type())
-		case fmt.Stringer:
-			rt = v.String()
 		case types.Type:
 			rt = r.r.Type(v)
 		case past.Type:
 			rt = r.r.Proto(v).Impl()
+		case fmt.Stringer:
+			rt = v.String()
 		default:
 			panic(fmt.Sprintf(
 				"single receiver value type can be string|fmt.String|%T|%T|%T, got %T",
@@ -341,12 +419,12 @@ func (r *GoFuncRenderer[T]) setReceiverInfo(rcvr ...any) {
 		switch v := rcvr[1].(type) {
 		case string:
 			rt = v
-		case fmt.Stringer:
-			rt = v.String()
 		case types.Type:
 			rt = r.r.Type(v)
 		case past.Type:
 			rt = r.r.Proto(v).Impl()
+		case fmt.Stringer:
+			rt = v.String()
 		default:
 			panic(fmt.Sprintf(
 				"receiver type parameter can be string|fmt.String|%T|%T, got %T",
@@ -483,14 +561,15 @@ func (r *GoFuncRenderer[T]) semiManualArguments(what string, params ...any) (res
 			panic(fmt.Sprintf("key value must not have %T type", new(types.Var)))
 		case string:
 			value = r.r.S(w)
-		case fmt.Stringer:
-			value = r.r.S(w.String())
 		case types.Type:
+			// Types implement fmt.Stringer as well, so they go first.
 			value = r.r.Type(w)
-			zero = zeroValueOfTypesType(r.r, w, i < (len(params)/2-1))
+			zero = zeroValueOfTypesType(r.r, w, i == len(params)/2-1)
 		case past.Type:
 			value = r.r.Proto(w).Impl()
-			zero = zeroValueOfProtoType(r.r, w)
+			zero = r.r.ProtoZero(w)
+		case fmt.Stringer:
+			value = r.r.S(w.String())
 		default:
 			panic(fmt.Sprintf(
 				"value type must one of of string|fmt.Stringer|%T|%T, got %T",
@@ -510,7 +589,7 @@ func (r *GoFuncRenderer[T]) semiManualArguments(what string, params ...any) (res
 }
 
 func (r *GoFuncRenderer[T]) takeVarName(what, name string) {
-	if name != "" {
+	if name == "" || name == "_" {
 		return
 	}
 
@@ -1,76 +1,80 @@
 package gogh
 
 import (
-	"fmt"
+	"go/ast"
+	"go/parser"
 	"go/types"
 
+	"github.com/sirkon/errors"
+	"github.com/sirkon/go-format/v2"
 	"github.com/sirkon/protoast/v2/past"
 
 	"github.com/sirkon/gogh/internal/consts"
 )
 
-func zeroValueOfTypesType[T Importer](r *GoRenderer[T], t types.Type, isLast bool) (res string) {
-	defer func() {
-		switch res {
-		case "":
-			res = "nil"
-		}
-	}()
+// Zero returns zero value expression of the given type. Packages of
+// named types are imported when needed.
+//
+//	r.L(`var $v $0 = $1`, t, r.Zero(t))
+//
+// Plain literals are used for types based on booleans, numbers and
+// strings, composite literals for structs and arrays, nil for the rest
+// and *new(T) for type parameters.
+func (r *GoRenderer[T]) Zero(t types.Type) string {
+	return zeroValueOfTypesType(r, t, false)
+}
 
-	switch v := t.(type) {
+// zeroValueOfTypesType computes zero value of the given type. Returns
+// consts.ErrorTypeZeroSign for error compatible interfaces when isLast is set.
+func zeroValueOfTypesType[T Importer](r *GoRenderer[T], t types.Type, isLast bool) string {
+	if _, ok := types.Unalias(t).(*types.TypeParam); ok {
+		return "*new(" + r.Type(t) + ")"
+	}
+
+	switch v := types.Unalias(t).Underlying().(type) {
 	case *types.Basic:
-		switch v.Kind() {
-		case types.Bool:
+		switch {
+		case v.Info()&types.IsBoolean != 0:
 			return "false"
-		case
-			types.Int, types.Int8, types.Int16, types.Int32, types.Int64,
-			types.Uint, types.Uint8, types.Uint16, types.Uint32, types.Uint64,
-			types.Uintptr,
-			types.Float32, types.Float64,
-			types.Complex64, types.Complex128:
-
-			return "0"
-		case types.String:
+		case v.Info()&types.IsString != 0:
 			return `""`
-		}
-	case *types.Named:
-		vv := zeroValueOfTypesType[T](r, v.Underlying(), isLast)
-		switch vv {
-		case "", consts.ErrorTypeZeroSign:
-			return vv
-		case "{}":
-			return r.Type(t) + vv
+		case v.Info()&types.IsNumeric != 0:
+			return "0"
 		default:
-			return r.S(`$0($1)`, r.Type(t))
+			// Untyped nil and unsafe.Pointer.
+			return "nil"
 		}
-	case *types.Array:
-		return r.Type(v) + "{}"
-	case *types.Slice, *types.Map, *types.Chan, *types.Pointer:
-		return
-	case *types.Struct:
-		return "{}"
+	case *types.Struct, *types.Array:
+		return r.Type(t) + "{}"
 	case *types.Interface:
-		if isErrorCompatibleInterface(v) {
+		if isLast && isErrorCompatibleInterface(v) {
 			return consts.ErrorTypeZeroSign
 		}
 
-		return
+		return "nil"
+	case *types.Pointer, *types.Slice, *types.Map, *types.Chan, *types.Signature:
+		return "nil"
 	default:
-		panic(fmt.Sprintf("unsupported type %T", t))
+		panic(errors.Newf("unsupported type %T", v))
 	}
-
-	panic("have no idea how did we get here")
 }
 
-func zeroValueOfProtoType[T Importer](r *GoRenderer[T], t past.Type) string {
-	switch t.(type) {
+// ProtoZero returns zero value expression of the protoc-gen-go generated
+// type for the given protobuf type. Packages of generated types are
+// imported when needed.
+//
+// Enums have their first value constant as a zero value, which is
+// always 0 in proto3. Messages, including google wrappers, bytes,
+// repeated and map types have nil zero value.
+func (r *GoRenderer[T]) ProtoZero(t past.Type) string {
+	switch v := t.(type) {
 	case *past.Bytes, *past.Repeated, *past.Map, *past.Message:
 		return "nil"
 
 	case *past.Bool:
 		return "false"
 
-	case *past.Enum,
+	case
 		*past.Int32, *past.Int64,
 		*past.Uint32, *past.Uint64,
 		*past.Fixed32, *past.Fixed64,
@@ -83,7 +87,142 @@ func zeroValueOfProtoType[T Importer](r *GoRenderer[T], t past.Type) string {
 	case *past.String:
 		return `""`
 
+	case *past.Enum:
+		for value := range v.Values(r.protoRegistry()) {
+			return r.Proto(value).String()
+		}
+
+		panic(errors.Newf("enum %s has no values", v.Name()))
+
+	case *past.EnumValue:
+		return r.ProtoZero(r.protoRegistry().NodeParent(v).(*past.Enum))
+
 	default:
-		panic(fmt.Sprintf("unsupported protobuf type %T", t))
+		panic(errors.Newf("unsupported protobuf type %T", t))
+	}
+}
+
+// resolveZeroes computes zero values heuristics have failed to compute.
+func (r *GoFuncRenderer[T]) resolveZeroes(zeroes []string) error {
+	for i, zero := range zeroes {
+		if zero != "" {
+			continue
+		}
+
+		name, typ := r.results[i][0], r.results[i][1]
+		for _, p := range r.tparams {
+			if p[0] == typ {
+				zeroes[i] = "*new(" + typ + ")"
+				break
+			}
+		}
+		if zeroes[i] != "" {
+			continue
+		}
+
+		if zeroes[i] = r.lookupZero(typ, i == len(zeroes)-1); zeroes[i] != "" {
+			continue
+		}
+
+		if name != "" && name != "_" {
+			zeroes[i] = name
+			continue
+		}
+
+		return errors.Newf("cannot compute zero value of %s %s result %d of type %s", r.kind(), r.name, i, typ)
+	}
+
+	return nil
+}
+
+// lookupZero computes zero value of a type given as a name of the
+// current package or a qualified name of an imported package. It
+// loads the package to find out what the type is. Returns an empty
+// string if this failed.
+func (r *GoFuncRenderer[T]) lookupZero(typ string, isLast bool) string {
+	expr, err := parser.ParseExpr(typ)
+	if err != nil {
+		return ""
 	}
+
+	switch v := expr.(type) {
+	case *ast.IndexExpr:
+		expr = v.X
+	case *ast.IndexListExpr:
+		expr = v.X
+	}
+
+	var pkgpath string
+	var name string
+	switch v := expr.(type) {
+	case *ast.Ident:
+		pkgpath = r.r.pkg.Path()
+		name = v.Name
+	case *ast.SelectorExpr:
+		alias, ok := v.X.(*ast.Ident)
+		if !ok {
+			return ""
+		}
+		for path, pkgalias := range r.r.imports.Imports().pkgs {
+			if pkgalias == alias.Name {
+				pkgpath = path
+				break
+			}
+		}
+		name = v.Sel.Name
+	default:
+		return ""
+	}
+	if pkgpath == "" {
+		return ""
+	}
+
+	pkg, err := r.r.pkg.mod.typesPackage(pkgpath)
+	if err != nil {
+		return ""
+	}
+	obj, ok := pkg.Scope().Lookup(name).(*types.TypeName)
+	if !ok {
+		return ""
+	}
+
+	switch v := obj.Type().Underlying().(type) {
+	case *types.Basic:
+		switch {
+		case v.Info()&types.IsBoolean != 0:
+			return "false"
+		case v.Info()&types.IsString != 0:
+			return `""`
+		case v.Info()&types.IsNumeric != 0:
+			return "0"
+		default:
+			return "nil"
+		}
+	case *types.Struct, *types.Array:
+		return typ + "{}"
+	case *types.Interface:
+		if isLast && isErrorCompatibleInterface(v) {
+			return consts.ErrorTypeZeroSign
+		}
+
+		return "nil"
+	default:
+		return "nil"
+	}
+}
+
+// unknownZeroValues is set as ReturnZeroValues when they cannot be computed.
+// It fails the formatting when used.
+type unknownZeroValues struct {
+	err error
+}
+
+// Clarify to implement format.Formatter.
+func (u unknownZeroValues) Clarify(string) (format.Formatter, error) {
+	return nil, u.err
+}
+
+// Format to implement format.Formatter.
+func (u unknownZeroValues) Format(string) (string, error) {
+	return "", u.err
 }
@@ -0,0 +1,104 @@
+package gogh
+
+import (
+	"go/types"
+	"strings"
+	"testing"
+)
+
+func TestGoFuncRendererZeroes(t *testing.T) {
+	tests := []struct {
+		name    string
+		tparams []any
+		results []any
+		want    string
+	}{
+		{
+			name:    "builtins",
+			results: []any{"int", "rune", "any", "[]int", "func() error", "error", ""},
+			want:    "0,0,nil,nil,nil,",
+		},
+		{
+			name:    "qualified",
+			results: []any{"$time.Duration", "$bytes.Buffer", "$io.Reader", "error", ""},
+			want:    "0,bytes.Buffer{},nil,",
+		},
+		{
+			name:    "type-params",
+			tparams: []any{"K", "comparable", "V", "any"},
+			results: []any{"K", "V", "map[K]V", "error", ""},
+			want:    "*new(K),*new(V),nil,",
+		},
+		{
+			name:    "named",
+			results: []any{"cfg", "Config", "n", "$time.Duration", "err", "error"},
+			want:    "cfg,0,",
+		},
+		{
+			name:    "error-not-last",
+			results: []any{"error", "int", ""},
+			want:    "nil,0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestPackage(t).Go("zeroes.go")
+			r.Imports().Add("time").Ref("time")
+			r.Imports().Add("bytes").Ref("bytes")
+			r.Imports().Add("io").Ref("io")
+
+			var got string
+			f := r.F("f")()
+			if len(tt.tparams) > 0 {
+				f.TypeParams(tt.tparams...)
+			}
+			f.Returns(tt.results...).Body(func(r *GoRenderer[*Imports]) {
+				got = strings.Join(strings.Fields(r.S("$"+ReturnZeroValues)), "")
+			})
+			if got != tt.want {
+				t.Errorf("unexpected zero values '%s', want '%s'", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGoFuncRendererUnknownZeroes(t *testing.T) {
+	r := newTestPackage(t).Go("zeroes.go")
+	r.F("f")().Returns("Config", "error", "").Body(func(r *GoRenderer[*Imports]) {
+		v, _ := r.vals.Get(ReturnZeroValues)
+		if _, ok := v.(unknownZeroValues); !ok {
+			t.Errorf("unknown zero values expected, got %T", v)
+		}
+	})
+}
+
+func TestGoFuncRendererTypesResults(t *testing.T) {
+	pkg := typesOf(t, `package source
+
+type D int64
+
+type S struct{}
+`)
+
+	r := newTestPackage(t).Go("results.go")
+	r.F("f")().Returns(
+		"d", pkg.Scope().Lookup("D").Type(),
+		"s", pkg.Scope().Lookup("S").Type(),
+		"err", types.Universe.Lookup("error").Type(),
+	).Body(func(r *GoRenderer[*Imports]) {
+		got := strings.Join(strings.Fields(r.S("$"+ReturnZeroValues)), "")
+		if got != "0,source.S{}," {
+			t.Errorf("unexpected zero values '%s'", got)
+		}
+		r.L(`return $ReturnZeroValues nil`)
+	})
+
+	const want = `func f() (d source.D, s source.S, err error) {
+	return 0, source.S{}, nil
+}
+`
+	if got := renderedCode(t, r); got != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,122 @@
+package gogh
+
+import (
+	"go/types"
+	"strings"
+
+	"github.com/sirkon/errors"
+)
+
+// Implement renders stubs of all methods of the given interface
+// for the given receiver. The iface can be either *types.Interface
+// or a named type (or alias) whose underlying type is an interface.
+// Methods of embedded interfaces are rendered as well and methods
+// of instantiated generic interfaces have their type arguments
+// substituted.
+//
+// The rcvr is a receiver as it would be passed into the M call
+// with a single argument. The body is called for each method with
+// a renderer scoped to that method's body, where ReturnZeroValues
+// is preset. A nil body renders methods returning zero values.
+//
+// Usage example:
+//
+//	r.Implement(iface, "s *storageStub", func(r *GoRenderer[T], m *types.Func) {
+//	    r.L(`return $ReturnZeroValues $errs.New("not implemented")`)
+//	})
+func (r *GoRenderer[T]) Implement(iface types.Type, rcvr any, body func(r *GoRenderer[T], method *types.Func)) {
+	it := interfaceOf(iface)
+
+	for i := 0; i < it.NumMethods(); i++ {
+		method := it.Method(i)
+		sig := method.Type().(*types.Signature)
+
+		if i > 0 {
+			r.N()
+		}
+
+		// Parameter and result names are to be made unique against
+		// the receiver name before the method rendering.
+		names := r.Scope()
+		if name := implementReceiverName(rcvr); name != "" {
+			names.Uniq(name)
+		}
+		sig = types.NewSignatureType(
+			nil,
+			nil,
+			nil,
+			implementTuple(names, sig.Params()),
+			implementTuple(names, sig.Results()),
+			sig.Variadic(),
+		)
+		method = types.NewFunc(method.Pos(), method.Pkg(), method.Name(), sig)
+
+		r.M(rcvr)(method.Name())(sig).Returns(sig.Results()).Body(func(r *GoRenderer[T]) {
+			if body != nil {
+				body(r, method)
+				return
+			}
+
+			if sig.Results().Len() > 0 {
+				r.L(`return $0`, r.zeroes(sig.Results()))
+			}
+		})
+	}
+}
+
+// zeroes renders zero values of the tuple as a comma separated list.
+func (r *GoRenderer[T]) zeroes(tuple *types.Tuple) string {
+	var values []string
+	for i := 0; i < tuple.Len(); i++ {
+		values = append(values, zeroValueOfTypesType(r, tuple.At(i).Type(), false))
+	}
+
+	return strings.Join(values, ", ")
+}
+
+// implementReceiverName returns the name of the receiver given in
+// the form accepted by Implement. Empty string is returned for
+// unnamed receivers.
+func implementReceiverName(rcvr any) string {
+	switch v := rcvr.(type) {
+	case string:
+		if parts := strings.Fields(v); len(parts) == 2 {
+			return parts[0]
+		}
+	case *types.Var:
+		return v.Name()
+	}
+
+	return ""
+}
+
+// implementTuple returns a copy of the tuple with its named variables
+// renamed to be unique in the given names scope.
+func implementTuple[T Importer](names *GoRenderer[T], tuple *types.Tuple) *types.Tuple {
+	var vars []*types.Var
+	for i := 0; i < tuple.Len(); i++ {
+		v := tuple.At(i)
+		name := v.Name()
+		if name != "" && name != "_" {
+			name = names.Uniq(name)
+		}
+		vars = append(vars, types.NewVar(v.Pos(), v.Pkg(), name, v.Type()))
+	}
+
+	return types.NewTuple(vars...)
+}
+
+func interfaceOf(t types.Type) *types.Interface {
+	switch v := types.Unalias(t).(type) {
+	case *types.Interface:
+		return v
+	case *types.Named:
+		if it, ok := v.Underlying().(*types.Interface); ok {
+			return it
+		}
+
+		panic(errors.Newf("type %s is not an interface", v))
+	default:
+		panic(errors.Newf("type %s is not an interface", t))
+	}
+}
@@ -0,0 +1,83 @@
+package gogh
+
+import (
+	"go/types"
+	"testing"
+)
+
+func TestGoRendererImplement(t *testing.T) {
+	src := typesOf(t, `package source
+
+import "context"
+
+type Closer interface {
+	Close() error
+}
+
+type Storage[K comparable, V any] interface {
+	Closer
+	Get(ctx context.Context, key K) (V, bool, error)
+	Keys(prefixes ...string) []K
+	Count() int
+}
+
+type IntStorage = Storage[int, string]
+
+type Setter interface {
+	Set(s string) (r int, err error)
+}
+`)
+
+	t.Run("generic instantiation", func(t *testing.T) {
+		r := newTestPackage(t).Go("storage.go")
+		r.Implement(src.Scope().Lookup("IntStorage").Type(), "s *stub", nil)
+
+		const want = `func (s *stub) Close() error {
+	return nil
+}
+
+func (s *stub) Count() int {
+	return 0
+}
+
+func (s *stub) Get(ctx context.Context, key int) (string, bool, error) {
+	return "", false, nil
+}
+
+func (s *stub) Keys(prefixes ...string) []int {
+	return nil
+}
+`
+		if got := renderedCode(t, r); got != want {
+			t.Errorf("unexpected output:\n%s\nwant:\n%s", got, want)
+		}
+	})
+
+	t.Run("custom body", func(t *testing.T) {
+		r := newTestPackage(t).Go("closer.go")
+		r.Imports().Add("errors").Ref("errs")
+		r.Implement(src.Scope().Lookup("Closer").Type(), "stub", func(r *GoRenderer[*Imports], m *types.Func) {
+			r.L(`return $ReturnZeroValues $errs.New("$0 is not implemented")`, m.Name())
+		})
+
+		const want = `func (stub) Close() error {
+	return errors.New("Close is not implemented")
+}
+`
+		if got := renderedCode(t, r); got != want {
+			t.Errorf("unexpected output:\n%s\nwant:\n%s", got, want)
+		}
+	})
+	t.Run("receiver name clash", func(t *testing.T) {
+		r := newTestPackage(t).Go("setter.go")
+		r.Implement(src.Scope().Lookup("Setter").Type(), "s *stub", nil)
+
+		const want = `func (s *stub) Set(s2 string) (r int, err error) {
+	return 0, nil
+}
+`
+		if got := renderedCode(t, r); got != want {
+			t.Errorf("unexpected output:\n%s\nwant:\n%s", got, want)
+		}
+	})
+}
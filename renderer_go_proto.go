@@ -53,3 +53,19 @@ func raw(value string) ProtocType {
 		selector: value,
 	}
 }
+
+// protoWellKnownPackages пакеты сгенерированных типов для well-known protobuf файлов.
+// Эти файлы идут вместе с protoc и не обязательно имеют go_package.
+var protoWellKnownPackages = map[string]string{
+	"google/protobuf/any.proto":            "google.golang.org/protobuf/types/known/anypb",
+	"google/protobuf/api.proto":            "google.golang.org/protobuf/types/known/apipb",
+	"google/protobuf/duration.proto":       "google.golang.org/protobuf/types/known/durationpb",
+	"google/protobuf/empty.proto":          "google.golang.org/protobuf/types/known/emptypb",
+	"google/protobuf/field_mask.proto":     "google.golang.org/protobuf/types/known/fieldmaskpb",
+	"google/protobuf/source_context.proto": "google.golang.org/protobuf/types/known/sourcecontextpb",
+	"google/protobuf/struct.proto":         "google.golang.org/protobuf/types/known/structpb",
+	"google/protobuf/timestamp.proto":      "google.golang.org/protobuf/types/known/timestamppb",
+	"google/protobuf/type.proto":           "google.golang.org/protobuf/types/known/typepb",
+	"google/protobuf/wrappers.proto":       "google.golang.org/protobuf/types/known/wrapperspb",
+	"google/protobuf/descriptor.proto":     "google.golang.org/protobuf/types/descriptorpb",
+}
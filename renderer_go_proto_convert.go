@@ -0,0 +1,553 @@
+package gogh
+
+import (
+	"go/types"
+	"slices"
+
+	"github.com/sirkon/errors"
+	"github.com/sirkon/protoast/v2/past"
+)
+
+// ProtoUnmatched fields left unmatched by ProtoConverters. Names are
+// qualified with names of their types, like User.Nick.
+type ProtoUnmatched struct {
+	Go    []string
+	Proto []string
+}
+
+// ProtoConverters renders functions converting the Go struct into the
+// protoc-gen-go generated struct of the message and back:
+//
+//	func UserToProto(src *User) *examplev1.User
+//	func UserFromProto(src *examplev1.User) *User
+//
+// Exported Go fields are matched with message fields by name: a Go field
+// matches a protobuf field if it is named after it either with Public or
+// with Proto. Scalars, enums, pointers, slices, maps, google wrappers,
+// Timestamp and Duration are supported. Nested messages are converted with
+// functions rendered for them the same way. Oneofs are not supported and
+// are reported as unmatched.
+//
+// Both functions keep nil as is. Converters are rendered once per package.
+// It panics if types of matched fields cannot be converted.
+func (r *GoRenderer[T]) ProtoConverters(t types.Type, msg *past.Message) ProtoUnmatched {
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok {
+		panic(errors.Newf("named type expected, got %s", t))
+	}
+	if _, ok := named.Underlying().(*types.Struct); !ok {
+		panic(errors.Newf("struct type expected, got %s", t))
+	}
+
+	c := &protoConverter[T]{
+		r: r,
+	}
+	c.converter(named, msg)
+	for len(c.pending) > 0 {
+		next := c.pending[0]
+		c.pending = c.pending[1:]
+		c.render(next)
+	}
+
+	return c.unmatched
+}
+
+type protoConverter[T Importer] struct {
+	r         *GoRenderer[T]
+	pending   []protoConversion
+	unmatched ProtoUnmatched
+}
+
+type protoConversion struct {
+	name string
+	typ  *types.Named
+	msg  *past.Message
+}
+
+// converter returns base name of converter functions for the given pair
+// and schedules their rendering if they were not rendered yet.
+func (c *protoConverter[T]) converter(t *types.Named, msg *past.Message) string {
+	key := types.TypeString(t, nil) + " " + c.r.protoRegistry().TypeName(msg)
+	if name, ok := c.r.pkg.protoConvs[key]; ok {
+		return name
+	}
+
+	name := t.Obj().Name()
+	for k, v := range c.r.pkg.protoConvs {
+		if v == name {
+			panic(errors.Newf("converters %sToProto and %sFromProto are already rendered for %s", name, name, k))
+		}
+	}
+
+	if c.r.pkg.protoConvs == nil {
+		c.r.pkg.protoConvs = map[string]string{}
+	}
+	c.r.pkg.protoConvs[key] = name
+	c.pending = append(c.pending, protoConversion{
+		name: name,
+		typ:  t,
+		msg:  msg,
+	})
+	return name
+}
+
+type protoConvField struct {
+	gf *types.Var
+	pf *past.MessageField
+}
+
+func (c *protoConverter[T]) render(conv protoConversion) {
+	r := c.r
+	fields := r.protoMessageFields(conv.msg)
+
+	var pairs []protoConvField
+	matched := map[*past.MessageField]struct{}{}
+	s := conv.typ.Underlying().(*types.Struct)
+	for i := 0; i < s.NumFields(); i++ {
+		gf := s.Field(i)
+		if !gf.Exported() {
+			continue
+		}
+
+		idx := slices.IndexFunc(fields, func(f *past.MessageField) bool {
+			if _, ok := f.Type(r.protoRegistry()).(*past.OneOf); ok {
+				return false
+			}
+			return gf.Name() == Public(f.Name()) || gf.Name() == protocGoName(f.Name())
+		})
+		if idx < 0 {
+			c.unmatched.Go = append(c.unmatched.Go, conv.typ.Obj().Name()+"."+gf.Name())
+			continue
+		}
+
+		matched[fields[idx]] = struct{}{}
+		pairs = append(pairs, protoConvField{
+			gf: gf,
+			pf: fields[idx],
+		})
+	}
+	for _, f := range fields {
+		if _, ok := matched[f]; !ok {
+			c.unmatched.Proto = append(c.unmatched.Proto, conv.msg.Name()+"."+f.Name())
+		}
+	}
+
+	goType := "*" + r.Type(conv.typ)
+	msgType := r.Proto(conv.msg).Impl()
+
+	if r.last().Len() > 0 {
+		r.N()
+	}
+	r.F(conv.name+"ToProto")("src", goType).Returns(msgType).Body(func(r *GoRenderer[T]) {
+		dst := r.Uniq("dst")
+		r.R(`if src == nil {`)
+		r.R(`    return nil`)
+		r.R(`}`)
+		r.N()
+		r.R(dst + ` := &` + r.Proto(conv.msg).String() + `{}`)
+		for _, p := range pairs {
+			c.field(r, p, true, dst)
+		}
+		r.N()
+		r.R(`return ` + dst)
+	})
+	r.N()
+
+	r.F(conv.name+"FromProto")("src", msgType).Returns(goType).Body(func(r *GoRenderer[T]) {
+		dst := r.Uniq("dst")
+		r.R(`if src == nil {`)
+		r.R(`    return nil`)
+		r.R(`}`)
+		r.N()
+		r.R(dst + ` := &` + r.Type(conv.typ) + `{}`)
+		for _, p := range pairs {
+			c.field(r, p, false, dst)
+		}
+		r.N()
+		r.R(`return ` + dst)
+	})
+}
+
+// field renders conversion of a single field.
+func (c *protoConverter[T]) field(r *GoRenderer[T], p protoConvField, toProto bool, dst string) {
+	gt := p.gf.Type()
+	pt := p.pf.Type(r.protoRegistry())
+
+	goSrc, goDst := "src."+p.gf.Name(), dst+"."+p.gf.Name()
+	pSrc, pDst := "src."+r.ProtoField(p.pf), dst+"."+r.ProtoField(p.pf)
+	src, target := goSrc, pDst
+	if !toProto {
+		src, target = pSrc, goDst
+	}
+
+	elem, isPointer := gt, false
+	if v, ok := types.Unalias(gt).(*types.Pointer); ok {
+		elem, isPointer = v.Elem(), true
+	}
+
+	fail := func() {
+		panic(errors.Newf(
+			"cannot convert field %s of type %s into protobuf field %s of type %s",
+			p.gf.Name(), gt, p.pf.Name(), r.Proto(pt).Impl(),
+		))
+	}
+
+	switch v := pt.(type) {
+	case *past.Repeated:
+		c.collection(r, gt, v, src, target, toProto, fail)
+		return
+	case *past.Map:
+		c.collection(r, gt, v, src, target, toProto, fail)
+		return
+	case *past.Message:
+		if expr, ok := c.value(gt, pt, src, toProto); ok {
+			if toProto || isPointer {
+				r.R(target + ` = ` + expr)
+				return
+			}
+
+			r.R(`if ` + src + ` != nil {`)
+			r.R(`    ` + target + ` = ` + expr)
+			r.R(`}`)
+			return
+		}
+		if !isPointer {
+			fail()
+		}
+
+		if toProto {
+			expr, ok := c.value(elem, pt, "*"+src, true)
+			if !ok {
+				fail()
+			}
+			r.R(`if ` + src + ` != nil {`)
+			r.R(`    ` + target + ` = ` + expr)
+			r.R(`}`)
+			return
+		}
+
+		expr, ok := c.value(elem, pt, src, false)
+		if !ok {
+			fail()
+		}
+		s := r.Scope()
+		tmp := s.Uniq(Private(p.gf.Name()))
+		s.R(`if ` + src + ` != nil {`)
+		s.R(`    ` + tmp + ` := ` + expr)
+		s.R(`    ` + target + ` = &` + tmp)
+		s.R(`}`)
+		return
+	}
+
+	presence := r.protoFieldHasPresence(p.pf)
+	switch {
+	case !isPointer && !presence:
+		expr, ok := c.value(gt, pt, src, toProto)
+		if !ok {
+			fail()
+		}
+		r.R(target + ` = ` + expr)
+
+	case isPointer && presence:
+		expr, ok := c.value(elem, pt, "*"+src, toProto)
+		if !ok {
+			fail()
+		}
+		s := r.Scope()
+		tmp := s.Uniq(Private(p.gf.Name()))
+		s.R(`if ` + src + ` != nil {`)
+		s.R(`    ` + tmp + ` := ` + expr)
+		s.R(`    ` + target + ` = &` + tmp)
+		s.R(`}`)
+
+	case toProto && isPointer:
+		// A nil pointer leaves zero value in the plain protobuf field.
+		expr, ok := c.value(elem, pt, "*"+src, true)
+		if !ok {
+			fail()
+		}
+		r.R(`if ` + src + ` != nil {`)
+		r.R(`    ` + target + ` = ` + expr)
+		r.R(`}`)
+
+	case !toProto && !isPointer:
+		// Unset protobuf field with presence gives zero value.
+		expr, ok := c.value(gt, pt, "src."+r.ProtoGetter(p.pf)+"()", false)
+		if !ok {
+			fail()
+		}
+		r.R(target + ` = ` + expr)
+
+	default:
+		expr, ok := c.value(elem, pt, src, toProto)
+		if !ok {
+			fail()
+		}
+		tmp := r.Uniq(Private(p.gf.Name()))
+		r.R(tmp + ` := ` + expr)
+		r.R(target + ` = &` + tmp)
+	}
+}
+
+// collection renders conversion of repeated and map fields.
+func (c *protoConverter[T]) collection(
+	r *GoRenderer[T],
+	gt types.Type,
+	pt past.Type,
+	src string,
+	target string,
+	toProto bool,
+	fail func(),
+) {
+	targetType := r.Proto(pt).Impl()
+	if !toProto {
+		targetType = r.Type(gt)
+	}
+
+	s := r.Scope()
+	k, v := s.Uniq("k"), s.Uniq("v")
+	switch p := pt.(type) {
+	case *past.Repeated:
+		sl, ok := gt.Underlying().(*types.Slice)
+		if !ok {
+			fail()
+		}
+		expr, ok := c.value(sl.Elem(), p.Type, v, toProto)
+		if !ok {
+			fail()
+		}
+		if expr == v {
+			r.R(target + ` = ` + src)
+			return
+		}
+
+		s.R(`if ` + src + ` != nil {`)
+		s.R(`    ` + target + ` = make(` + targetType + `, 0, len(` + src + `))`)
+		s.R(`    for _, ` + v + ` := range ` + src + ` {`)
+		expr = c.element(s, sl.Elem(), p.Type, v, expr, toProto)
+		s.R(`        ` + target + ` = append(` + target + `, ` + expr + `)`)
+		s.R(`    }`)
+		s.R(`}`)
+
+	case *past.Map:
+		m, ok := gt.Underlying().(*types.Map)
+		if !ok {
+			fail()
+		}
+		key, ok := c.value(m.Key(), p.Key(), k, toProto)
+		if !ok {
+			fail()
+		}
+		value, ok := c.value(m.Elem(), p.Value(r.protoRegistry()), v, toProto)
+		if !ok {
+			fail()
+		}
+		if key == k && value == v {
+			r.R(target + ` = ` + src)
+			return
+		}
+
+		s.R(`if ` + src + ` != nil {`)
+		s.R(`    ` + target + ` = make(` + targetType + `, len(` + src + `))`)
+		s.R(`    for ` + k + `, ` + v + ` := range ` + src + ` {`)
+		value = c.element(s, m.Elem(), p.Value(r.protoRegistry()), v, value, toProto)
+		s.R(`        ` + target + `[` + key + `] = ` + value)
+		s.R(`    }`)
+		s.R(`}`)
+	}
+}
+
+// element returns an expression of the collection element conversion. Nil
+// messages cannot be dereferenced into non-pointer Go values, so they are
+// converted into a zero value within the loop body instead.
+func (c *protoConverter[T]) element(s *GoRenderer[T], gt types.Type, pt past.Type, v, expr string, toProto bool) string {
+	if _, ok := pt.(*past.Message); !ok || toProto || expr == v {
+		return expr
+	}
+	if _, ok := types.Unalias(gt).(*types.Pointer); ok {
+		return expr
+	}
+
+	item := s.Uniq("item")
+	s.R(`        var ` + item + ` ` + s.Type(gt))
+	s.R(`        if ` + v + ` != nil {`)
+	s.R(`            ` + item + ` = ` + expr)
+	s.R(`        }`)
+	return item
+}
+
+// value returns an expression converting expr of one type into another.
+// Pointers are only supported for nested messages.
+func (c *protoConverter[T]) value(gt types.Type, pt past.Type, expr string, toProto bool) (string, bool) {
+	r := c.r
+	switch v := pt.(type) {
+	case *past.Enum:
+		if !protoConvIsInteger(gt) {
+			return "", false
+		}
+		if toProto {
+			return r.Proto(v).String() + "(" + expr + ")", true
+		}
+		return r.Type(gt) + "(" + expr + ")", true
+
+	case *past.Message:
+		registry := r.protoRegistry()
+		pkg := r.Proto(v).Pkg()
+		switch name := registry.TypeName(v); name {
+		case ".google.protobuf.Timestamp", ".google.protobuf.Duration":
+			want := "Time"
+			getter := ".AsTime()"
+			if name == ".google.protobuf.Duration" {
+				want = "Duration"
+				getter = ".AsDuration()"
+			}
+			if goTimeType(gt) != want {
+				return "", false
+			}
+			if toProto {
+				return pkg + ".New(" + expr + ")", true
+			}
+			return expr + getter, true
+		}
+
+		if w, ok := protoWrappers[registry.TypeName(v)]; ok {
+			if toProto {
+				value, ok := protoConvBasic(r, gt, w.typ, expr)
+				return pkg + "." + w.ctor + "(" + value + ")", ok
+			}
+			return protoConvBasic(r, w.typ, gt, expr+".GetValue()")
+		}
+
+		target, isPointer := types.Unalias(gt), false
+		if p, ok := target.(*types.Pointer); ok {
+			target, isPointer = types.Unalias(p.Elem()), true
+		}
+		named, ok := target.(*types.Named)
+		if !ok {
+			return "", false
+		}
+		if _, ok := named.Underlying().(*types.Struct); !ok || goTimeType(named) != "" {
+			return "", false
+		}
+
+		name := c.converter(named, v)
+		switch {
+		case toProto && isPointer:
+			return name + "ToProto(" + expr + ")", true
+		case toProto:
+			return name + "ToProto(&" + expr + ")", true
+		case isPointer:
+			return name + "FromProto(" + expr + ")", true
+		default:
+			return "*" + name + "FromProto(" + expr + ")", true
+		}
+
+	case *past.Repeated, *past.Map, *past.OneOf:
+		return "", false
+
+	default:
+		scalar := protoConvScalar(pt)
+		if scalar == nil {
+			return "", false
+		}
+		if toProto {
+			return protoConvBasic(r, gt, scalar, expr)
+		}
+		return protoConvBasic(r, scalar, gt, expr)
+	}
+}
+
+type protoWrapper struct {
+	ctor string
+	typ  types.Type
+}
+
+// protoWrappers constructors and value types of google wrappers.
+var protoWrappers = map[string]protoWrapper{
+	".google.protobuf.DoubleValue": {"Double", types.Typ[types.Float64]},
+	".google.protobuf.FloatValue":  {"Float", types.Typ[types.Float32]},
+	".google.protobuf.Int64Value":  {"Int64", types.Typ[types.Int64]},
+	".google.protobuf.UInt64Value": {"UInt64", types.Typ[types.Uint64]},
+	".google.protobuf.Int32Value":  {"Int32", types.Typ[types.Int32]},
+	".google.protobuf.UInt32Value": {"UInt32", types.Typ[types.Uint32]},
+	".google.protobuf.BoolValue":   {"Bool", types.Typ[types.Bool]},
+	".google.protobuf.StringValue": {"String", types.Typ[types.String]},
+	".google.protobuf.BytesValue":  {"Bytes", types.NewSlice(types.Typ[types.Byte])},
+}
+
+// protoConvScalar returns Go type of the scalar protobuf type.
+func protoConvScalar(t past.Type) types.Type {
+	switch t.(type) {
+	case *past.Int32, *past.Sint32, *past.Sfixed32:
+		return types.Typ[types.Int32]
+	case *past.Int64, *past.Sint64, *past.Sfixed64:
+		return types.Typ[types.Int64]
+	case *past.Uint32, *past.Fixed32:
+		return types.Typ[types.Uint32]
+	case *past.Uint64, *past.Fixed64:
+		return types.Typ[types.Uint64]
+	case *past.Float:
+		return types.Typ[types.Float32]
+	case *past.Double:
+		return types.Typ[types.Float64]
+	case *past.Bool:
+		return types.Typ[types.Bool]
+	case *past.String:
+		return types.Typ[types.String]
+	case *past.Bytes:
+		return types.NewSlice(types.Typ[types.Byte])
+	default:
+		return nil
+	}
+}
+
+// protoConvBasic returns an expression converting expr of type from into type to.
+// Types must either share an underlying type or be both numeric.
+func protoConvBasic[T Importer](r *GoRenderer[T], from, to types.Type, expr string) (string, bool) {
+	if types.Identical(from, to) {
+		return expr, true
+	}
+
+	if types.Identical(from.Underlying(), to.Underlying()) {
+		return r.Type(to) + "(" + expr + ")", true
+	}
+
+	fb, ok := from.Underlying().(*types.Basic)
+	if !ok {
+		return "", false
+	}
+	tb, ok := to.Underlying().(*types.Basic)
+	if !ok {
+		return "", false
+	}
+	if fb.Info()&types.IsNumeric == 0 || tb.Info()&types.IsNumeric == 0 {
+		return "", false
+	}
+
+	return r.Type(to) + "(" + expr + ")", true
+}
+
+func protoConvIsInteger(t types.Type) bool {
+	b, ok := t.Underlying().(*types.Basic)
+	return ok && b.Info()&types.IsInteger != 0
+}
+
+// goTimeType returns "Time" or "Duration" for time.Time and time.Duration
+// and an empty string for other types.
+func goTimeType(t types.Type) string {
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok {
+		return ""
+	}
+
+	obj := named.Obj()
+	if obj.Pkg() == nil || obj.Pkg().Path() != "time" {
+		return ""
+	}
+	switch obj.Name() {
+	case "Time", "Duration":
+		return obj.Name()
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,283 @@
+package gogh
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/sirkon/protoast/v2/past"
+)
+
+func TestGoRendererProtoConverters(t *testing.T) {
+	p := newTestPackage(t)
+	file := testProtos(t, p)
+	pkg := typesOf(t, `package source
+
+import "time"
+
+type ID int32
+
+type Role int
+
+type Address struct {
+	City string
+	Zip  string
+}
+
+type User struct {
+	ID         ID
+	Name       string
+	Role       Role
+	Status     *int
+	Address    *Address
+	Tags       []string
+	Counters   map[string]int
+	Nick       *string
+	Avatar     []byte
+	Score      int64
+	PrevStatus *int32
+	CreatedAt  time.Time
+	TTL        time.Duration
+	Age        int
+}
+`)
+
+	r := p.Go("convert.go")
+	unmatched := r.ProtoConverters(pkg.Scope().Lookup("User").Type(), file.Message(p.mod.registry, "User"))
+
+	want := `func UserToProto(src *source.User) *examplev1.User {
+	if src == nil {
+		return nil
+	}
+
+	dst := &examplev1.User{}
+	dst.Id = int64(src.ID)
+	dst.Name = src.Name
+	dst.Role = examplev1.User_Role(src.Role)
+	if src.Status != nil {
+		dst.Status = examplev1.Status(*src.Status)
+	}
+	dst.Address = AddressToProto(src.Address)
+	dst.Tags = src.Tags
+	if src.Counters != nil {
+		dst.Counters = make(map[string]int64, len(src.Counters))
+		for k, v := range src.Counters {
+			dst.Counters[k] = int64(v)
+		}
+	}
+	if src.Nick != nil {
+		dst.Nick = wrapperspb.String(*src.Nick)
+	}
+	dst.Avatar = src.Avatar
+	score := src.Score
+	dst.Score = &score
+	if src.PrevStatus != nil {
+		prevStatus := examplev1.Status(*src.PrevStatus)
+		dst.PrevStatus = &prevStatus
+	}
+	dst.CreatedAt = timestamppb.New(src.CreatedAt)
+	dst.Ttl = durationpb.New(src.TTL)
+
+	return dst
+}
+
+func UserFromProto(src *examplev1.User) *source.User {
+	if src == nil {
+		return nil
+	}
+
+	dst := &source.User{}
+	dst.ID = source.ID(src.Id)
+	dst.Name = src.Name
+	dst.Role = source.Role(src.Role)
+	status := int(src.Status)
+	dst.Status = &status
+	dst.Address = AddressFromProto(src.Address)
+	dst.Tags = src.Tags
+	if src.Counters != nil {
+		dst.Counters = make(map[string]int, len(src.Counters))
+		for k, v := range src.Counters {
+			dst.Counters[k] = int(v)
+		}
+	}
+	if src.Nick != nil {
+		nick := src.Nick.GetValue()
+		dst.Nick = &nick
+	}
+	dst.Avatar = src.Avatar
+	dst.Score = src.GetScore()
+	if src.PrevStatus != nil {
+		prevStatus := int32(*src.PrevStatus)
+		dst.PrevStatus = &prevStatus
+	}
+	if src.CreatedAt != nil {
+		dst.CreatedAt = src.CreatedAt.AsTime()
+	}
+	if src.Ttl != nil {
+		dst.TTL = src.Ttl.AsDuration()
+	}
+
+	return dst
+}
+
+func AddressToProto(src *source.Address) *examplev1.User_Address {
+	if src == nil {
+		return nil
+	}
+
+	dst := &examplev1.User_Address{}
+	dst.City = src.City
+
+	return dst
+}
+
+func AddressFromProto(src *examplev1.User_Address) *source.Address {
+	if src == nil {
+		return nil
+	}
+
+	dst := &source.Address{}
+	dst.City = src.City
+
+	return dst
+}
+`
+	if got := renderedCode(t, r); got != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", got, want)
+	}
+
+	if want := []string{"User.Age", "Address.Zip"}; !slices.Equal(unmatched.Go, want) {
+		t.Errorf("unexpected unmatched Go fields %v, want %v", unmatched.Go, want)
+	}
+	wantProto := []string{"User.signature", "User.extra", "User.nothing", "User.meta", "User.value", "User.mask", "User.contact"}
+	if !slices.Equal(unmatched.Proto, wantProto) {
+		t.Errorf("unexpected unmatched protobuf fields %v, want %v", unmatched.Proto, wantProto)
+	}
+
+	// Converters are rendered once per package.
+	address := file.Message(p.mod.registry, "User").Field(p.mod.registry, "address").Type(p.mod.registry).(*past.Message)
+	other := p.Go("other.go")
+	other.ProtoConverters(pkg.Scope().Lookup("Address").Type(), address)
+	if got := renderedCode(t, other); got != "" {
+		t.Errorf("converters must not be rendered twice, got:\n%s", got)
+	}
+}
+
+func TestGoRendererProtoConvertersCollections(t *testing.T) {
+	p := newTestPackage(t)
+	file := testProtos(t, p)
+	pkg := typesOf(t, `package source
+
+type Address struct {
+	City string
+}
+
+type Team struct {
+	Addresses []Address
+	Offices   map[string]Address
+}
+`)
+
+	r := p.Go("convert.go")
+	r.ProtoConverters(pkg.Scope().Lookup("Team").Type(), file.Message(p.mod.registry, "Team"))
+
+	want := `func TeamToProto(src *source.Team) *examplev1.Team {
+	if src == nil {
+		return nil
+	}
+
+	dst := &examplev1.Team{}
+	if src.Addresses != nil {
+		dst.Addresses = make([]*examplev1.Office, 0, len(src.Addresses))
+		for _, v := range src.Addresses {
+			dst.Addresses = append(dst.Addresses, AddressToProto(&v))
+		}
+	}
+	if src.Offices != nil {
+		dst.Offices = make(map[string]*examplev1.Office, len(src.Offices))
+		for k, v := range src.Offices {
+			dst.Offices[k] = AddressToProto(&v)
+		}
+	}
+
+	return dst
+}
+
+func TeamFromProto(src *examplev1.Team) *source.Team {
+	if src == nil {
+		return nil
+	}
+
+	dst := &source.Team{}
+	if src.Addresses != nil {
+		dst.Addresses = make([]source.Address, 0, len(src.Addresses))
+		for _, v := range src.Addresses {
+			var item source.Address
+			if v != nil {
+				item = *AddressFromProto(v)
+			}
+			dst.Addresses = append(dst.Addresses, item)
+		}
+	}
+	if src.Offices != nil {
+		dst.Offices = make(map[string]source.Address, len(src.Offices))
+		for k, v := range src.Offices {
+			var item source.Address
+			if v != nil {
+				item = *AddressFromProto(v)
+			}
+			dst.Offices[k] = item
+		}
+	}
+
+	return dst
+}
+
+func AddressToProto(src *source.Address) *examplev1.Office {
+	if src == nil {
+		return nil
+	}
+
+	dst := &examplev1.Office{}
+	dst.City = src.City
+
+	return dst
+}
+
+func AddressFromProto(src *examplev1.Office) *source.Address {
+	if src == nil {
+		return nil
+	}
+
+	dst := &source.Address{}
+	dst.City = src.City
+
+	return dst
+}
+`
+	if got := renderedCode(t, r); got != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestGoRendererProtoConvertersMismatch(t *testing.T) {
+	p := newTestPackage(t)
+	file := testProtos(t, p)
+	pkg := typesOf(t, `package source
+
+type User struct {
+	Name []int
+}
+`)
+
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatal("panic expected")
+		}
+		if err, ok := r.(error); !ok || !strings.Contains(err.Error(), "cannot convert field Name") {
+			t.Errorf("unexpected panic: %v", r)
+		}
+	}()
+	p.Go("convert.go").ProtoConverters(pkg.Scope().Lookup("User").Type(), file.Message(p.mod.registry, "User"))
+}
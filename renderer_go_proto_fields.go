@@ -0,0 +1,82 @@
+package gogh
+
+import (
+	"github.com/sirkon/errors"
+	"github.com/sirkon/protoast/v2/past"
+)
+
+// ProtoFieldType renders a type of the protoc-gen-go generated struct field
+// for the given message field. It differs from Proto of the field type for
+// fields with explicit presence: optional scalars and enums are pointers.
+// Field labels are read from proto sources, see WithProtoResolvers.
+//
+// Oneof fields have the type of their interface, see ProtoOneofIface.
+func (r *GoRenderer[T]) ProtoFieldType(f *past.MessageField) ProtocType {
+	typ := f.Type(r.protoRegistry())
+	switch typ.(type) {
+	case *past.OneOf:
+		return r.ProtoOneofIface(f)
+	case *past.Bytes, *past.Message, *past.Repeated, *past.Map:
+		return r.Proto(typ)
+	}
+
+	res := r.Proto(typ)
+	if r.protoFieldHasPresence(f) {
+		res.pointer = true
+	}
+
+	return res
+}
+
+// ProtoOneofBranch renders a name of the protoc-gen-go generated wrapper type
+// of the oneof branch:
+//
+//	message Event {
+//	    oneof payload {
+//	        Created created = 1;
+//	    }
+//	}
+//
+// The wrapper type of the created branch is *Event_Created. It gets "_"
+// suffix if it clashes with a nested type name.
+func (r *GoRenderer[T]) ProtoOneofBranch(b *past.OneOfBranch) ProtocType {
+	msg, name, _ := r.protoFieldOwner(b)
+
+	res := r.Proto(msg)
+	res.pointer = true
+	res.selector += "_" + r.protoMessageNames(msg).wrappers[name]
+	return res
+}
+
+// protoFieldHasPresence checks if the field is marked as optional or
+// required.
+func (r *GoRenderer[T]) protoFieldHasPresence(f *past.MessageField) bool {
+	pos := r.protoRegistry().Pos(f)
+	src, err := r.pkg.mod.protoSource(pos.Filename)
+	if err != nil {
+		panic(errors.Wrapf(err, "get label of field %s", f.Name()))
+	}
+
+	_, ok := src.labeled[protoPos{line: pos.Line, column: pos.Column}]
+	return ok
+}
+
+// protoMessageFields returns all fields of the message including oneofs in
+// their declaration order. Message.Fields of past skips oneofs.
+func (r *GoRenderer[T]) protoMessageFields(msg *past.Message) []*past.MessageField {
+	registry := r.protoRegistry()
+	pos := registry.Pos(msg)
+	src, err := r.pkg.mod.protoSource(pos.Filename)
+	if err != nil {
+		panic(errors.Wrapf(err, "get fields of message %s", msg.Name()))
+	}
+
+	var res []*past.MessageField
+	for _, name := range src.fields[protoPos{line: pos.Line, column: pos.Column}] {
+		if f := msg.Field(registry, name); f != nil {
+			res = append(res, f)
+		}
+	}
+
+	return res
+}
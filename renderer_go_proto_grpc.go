@@ -0,0 +1,146 @@
+package gogh
+
+import (
+	"github.com/sirkon/errors"
+	"github.com/sirkon/protoast/v2/past"
+)
+
+// ProtoService returns names of protoc-gen-go-grpc generated entities of
+// the given service. Names are qualified and imports are added the same
+// way Proto does.
+//
+//	s := r.ProtoService(service)
+//	r.L(`$0($conn)`, s.NewClient())
+func (r *GoRenderer[T]) ProtoService(s *past.Service) ProtoGRPCService {
+	var alias string
+	if !r.isInSamePackage(s) {
+		alias = r.imports.Add(r.protocTypePkgPath(s)).push()
+	}
+
+	return ProtoGRPCService{
+		source: alias,
+		name:   protocGoName(s.Name()),
+	}
+}
+
+// ProtoMethod returns names of protoc-gen-go-grpc generated entities of
+// the given service method.
+func (r *GoRenderer[T]) ProtoMethod(m *past.Method) ProtoGRPCMethod {
+	service, ok := r.protoRegistry().NodeParent(m).(*past.Service)
+	if !ok {
+		panic(errors.Newf("method %s is not within a service", m.Name()))
+	}
+
+	clientStream, _ := m.Input(r.protoRegistry())
+	serverStream, _ := m.Output(r.protoRegistry())
+	return ProtoGRPCMethod{
+		service:      r.ProtoService(service),
+		name:         protocGoName(m.Name()),
+		clientStream: clientStream,
+		serverStream: serverStream,
+	}
+}
+
+// ProtoGRPCService names of protoc-gen-go-grpc generated entities of a service.
+type ProtoGRPCService struct {
+	source string
+	name   string
+}
+
+// Name returns Go name of the service.
+func (s ProtoGRPCService) Name() string {
+	return s.name
+}
+
+// Client returns the client interface type: FooClient.
+func (s ProtoGRPCService) Client() ProtocType {
+	return s.named(s.name + "Client")
+}
+
+// NewClient returns the client constructor: NewFooClient.
+func (s ProtoGRPCService) NewClient() ProtocType {
+	return s.named("New" + s.name + "Client")
+}
+
+// Server returns the server interface type: FooServer.
+func (s ProtoGRPCService) Server() ProtocType {
+	return s.named(s.name + "Server")
+}
+
+// UnimplementedServer returns the type to be embedded into server
+// implementations: UnimplementedFooServer.
+func (s ProtoGRPCService) UnimplementedServer() ProtocType {
+	return s.named("Unimplemented" + s.name + "Server")
+}
+
+// UnsafeServer returns the interface opting out of forward compatibility
+// of server implementations: UnsafeFooServer.
+func (s ProtoGRPCService) UnsafeServer() ProtocType {
+	return s.named("Unsafe" + s.name + "Server")
+}
+
+// RegisterServer returns the server registration function: RegisterFooServer.
+func (s ProtoGRPCService) RegisterServer() ProtocType {
+	return s.named("Register" + s.name + "Server")
+}
+
+// ServiceDesc returns the service descriptor variable: Foo_ServiceDesc.
+func (s ProtoGRPCService) ServiceDesc() ProtocType {
+	return s.named(s.name + "_ServiceDesc")
+}
+
+func (s ProtoGRPCService) named(name string) ProtocType {
+	return ProtocType{
+		source:   s.source,
+		selector: name,
+	}
+}
+
+// ProtoGRPCMethod names of protoc-gen-go-grpc generated entities of a service method.
+type ProtoGRPCMethod struct {
+	service      ProtoGRPCService
+	name         string
+	clientStream bool
+	serverStream bool
+}
+
+// Name returns Go name of the method in client and server interfaces.
+func (m ProtoGRPCMethod) Name() string {
+	return m.name
+}
+
+// Service returns names of the method service.
+func (m ProtoGRPCMethod) Service() ProtoGRPCService {
+	return m.service
+}
+
+// IsStreaming checks if either the method input or its output is a stream.
+func (m ProtoGRPCMethod) IsStreaming() bool {
+	return m.clientStream || m.serverStream
+}
+
+// FullMethodName returns the constant with the full method name:
+// Foo_Bar_FullMethodName.
+func (m ProtoGRPCMethod) FullMethodName() ProtocType {
+	return m.service.named(m.service.name + "_" + m.name + "_FullMethodName")
+}
+
+// ClientStream returns the stream type used by the client of a
+// streaming method: Foo_BarClient.
+func (m ProtoGRPCMethod) ClientStream() ProtocType {
+	m.checkStreaming()
+	return m.service.named(m.service.name + "_" + m.name + "Client")
+}
+
+// ServerStream returns the stream type used by the server of a
+// streaming method: Foo_BarServer.
+func (m ProtoGRPCMethod) ServerStream() ProtocType {
+	m.checkStreaming()
+	return m.service.named(m.service.name + "_" + m.name + "Server")
+}
+
+func (m ProtoGRPCMethod) checkStreaming() {
+	if !m.IsStreaming() {
+		panic(errors.Newf("method %s.%s is not streaming and has no stream types", m.service.name, m.name))
+	}
+}
@@ -0,0 +1,166 @@
+package gogh
+
+import (
+	"github.com/sirkon/errors"
+	"github.com/sirkon/protoast/v2/past"
+)
+
+// ProtoField returns a name of the protoc-gen-go generated struct field
+// for the given message field or oneof branch. Oneof fields have their
+// own struct field of an oneof interface type, see ProtoOneofIface.
+//
+// Names are computed with respect to protoc-gen-go collision rules:
+// names of generated methods like Reset or String and names having
+// their getter clashed with another field get "_" suffix.
+func (r *GoRenderer[T]) ProtoField(f past.FieldNode) string {
+	msg, name, isOneof := r.protoFieldOwner(f)
+	names := r.protoMessageNames(msg)
+	if isOneof {
+		return names.oneofs[name]
+	}
+
+	return names.fields[name]
+}
+
+// ProtoGetter returns a name of the protoc-gen-go generated getter of
+// the given message field or oneof branch. The getter of oneof field
+// returns its oneof interface value.
+func (r *GoRenderer[T]) ProtoGetter(f past.FieldNode) string {
+	return "Get" + r.ProtoField(f)
+}
+
+// ProtoOneofIface renders the name of the protoc-gen-go generated
+// interface of the oneof field. Beware, it is not exported.
+//
+//	message Event {
+//	    oneof payload {
+//	        …
+//	    }
+//	}
+//
+// The interface name is isEvent_Payload.
+func (r *GoRenderer[T]) ProtoOneofIface(f *past.MessageField) ProtocType {
+	msg, name, isOneof := r.protoFieldOwner(f)
+	if !isOneof {
+		panic(errors.Newf("field %s of %s is not an oneof", f.Name(), msg.Name()))
+	}
+
+	res := r.Proto(msg)
+	res.pointer = false
+	res.selector = "is" + res.selector + "_" + r.protoMessageNames(msg).oneofs[name]
+	return res
+}
+
+// protoFieldOwner returns the message of the field or oneof branch, the
+// name of the field and if it is an oneof field.
+func (r *GoRenderer[T]) protoFieldOwner(f past.FieldNode) (*past.Message, string, bool) {
+	registry := r.protoRegistry()
+
+	switch v := f.(type) {
+	case *past.MessageField:
+		msg, ok := registry.NodeParent(v).(*past.Message)
+		if !ok {
+			panic(errors.Newf("field %s is not within a message", v.Name()))
+		}
+
+		_, isOneof := v.Type(registry).(*past.OneOf)
+		return msg, v.Name(), isOneof
+	case *past.OneOfBranch:
+		msg, ok := registry.NodeParent(registry.NodeParent(v)).(*past.Message)
+		if !ok {
+			panic(errors.Newf("oneof branch %s is not within a message", v.Name()))
+		}
+
+		return msg, v.Name(), false
+	default:
+		panic(errors.Newf("unsupported field node %T", f))
+	}
+}
+
+// protoMessageNames Go names of message fields computed the way
+// protoc-gen-go does.
+type protoMessageNames struct {
+	// fields maps names of fields and oneof branches to Go names.
+	fields map[string]string
+	// oneofs maps names of oneof fields to Go names.
+	oneofs map[string]string
+	// wrappers maps names of oneof branches to names of their wrapper types
+	// with the message type name prefix omitted.
+	wrappers map[string]string
+}
+
+// protoMessageNames computes Go names of fields of the message.
+func (r *GoRenderer[T]) protoMessageNames(msg *past.Message) protoMessageNames {
+	registry := r.protoRegistry()
+	res := protoMessageNames{
+		fields:   map[string]string{},
+		oneofs:   map[string]string{},
+		wrappers: map[string]string{},
+	}
+
+	// Methods of generated messages.
+	used := map[string]bool{
+		"Reset":               true,
+		"String":              true,
+		"ProtoMessage":        true,
+		"Marshal":             true,
+		"Unmarshal":           true,
+		"ExtensionRangeArray": true,
+		"ExtensionMap":        true,
+		"Descriptor":          true,
+	}
+	unique := func(name string, hasGetter bool) string {
+		for used[name] || (hasGetter && used["Get"+name]) {
+			name += "_"
+		}
+		used[name] = true
+		used["Get"+name] = hasGetter
+		return name
+	}
+
+	for _, field := range r.protoMessageFields(msg) {
+		oneof, ok := field.Type(registry).(*past.OneOf)
+		if !ok {
+			res.fields[field.Name()] = unique(protocGoName(field.Name()), true)
+			continue
+		}
+
+		first := true
+		for branch := range oneof.Branches(registry) {
+			res.fields[branch.Name()] = unique(protocGoName(branch.Name()), true)
+			if first {
+				// Oneofs are treated as if they have no getters, this
+				// is how protoc-gen-go does this for historical reasons.
+				res.oneofs[field.Name()] = unique(protocGoName(field.Name()), false)
+				first = false
+			}
+		}
+	}
+
+	// Wrapper types of oneof branches must not clash with nested types.
+	nested := map[string]bool{}
+	for typ := range msg.Types(registry) {
+		switch v := typ.(type) {
+		case *past.Message:
+			nested[protocGoName(v.Name())] = true
+		case *past.Enum:
+			nested[protocGoName(v.Name())] = true
+		}
+	}
+	for _, field := range r.protoMessageFields(msg) {
+		oneof, ok := field.Type(registry).(*past.OneOf)
+		if !ok {
+			continue
+		}
+
+		for branch := range oneof.Branches(registry) {
+			name := res.fields[branch.Name()]
+			for nested[name] {
+				name += "_"
+			}
+			res.wrappers[branch.Name()] = name
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,266 @@
+package gogh
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sirkon/protoast/v2/past"
+)
+
+func TestGoRendererProtoZero(t *testing.T) {
+	p := newTestPackage(t)
+	file := testProtos(t, p)
+	registry := p.mod.registry
+	user := file.Message(registry, "User")
+
+	tests := []struct {
+		field string
+		want  string
+	}{
+		{field: "id", want: "0"},
+		{field: "name", want: `""`},
+		{field: "role", want: "examplev1.User_ROLE_GUEST"},
+		{field: "status", want: "examplev1.Status_STATUS_UNKNOWN"},
+		{field: "address", want: "nil"},
+		{field: "tags", want: "nil"},
+		{field: "counters", want: "nil"},
+		{field: "nick", want: "nil"},
+		{field: "avatar", want: "nil"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			r := p.Go(tt.field + ".go")
+			if got := r.ProtoZero(user.Field(registry, tt.field).Type(registry)); got != tt.want {
+				t.Errorf("unexpected zero value '%s', want '%s'", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("enum-value", func(t *testing.T) {
+		r := p.Go("enum_value.go")
+		value := file.Enum(registry, "Status").Value(registry, "STATUS_ACTIVE")
+		if got := r.ProtoZero(value); got != "examplev1.Status_STATUS_UNKNOWN" {
+			t.Errorf("unexpected zero value '%s'", got)
+		}
+	})
+
+	t.Run("returns-scalars", func(t *testing.T) {
+		r := p.Go("returns_scalars.go")
+		r.F("f")().Returns("d", &past.Double{}, "b", &past.Bytes{}, "err", "error").Body(func(r *GoRenderer[*Imports]) {
+			got := strings.Join(strings.Fields(r.S("$"+ReturnZeroValues)), "")
+			if got != "0,nil," {
+				t.Errorf("unexpected zero values '%s'", got)
+			}
+			r.L(`return $ReturnZeroValues nil`)
+		})
+
+		const want = `func f() (d float64, b []byte, err error) {
+	return 0, nil, nil
+}
+`
+		if got := renderedCode(t, r); got != want {
+			t.Errorf("unexpected output:\n%s\nwant:\n%s", got, want)
+		}
+	})
+
+	t.Run("returns", func(t *testing.T) {
+		r := p.Go("returns.go")
+		status := user.Field(registry, "status").Type(registry)
+		r.F("f")().Returns("st", status, "err", "error").Body(func(r *GoRenderer[*Imports]) {
+			got := strings.Join(strings.Fields(r.S("$"+ReturnZeroValues)), "")
+			if got != "examplev1.Status_STATUS_UNKNOWN," {
+				t.Errorf("unexpected zero values '%s'", got)
+			}
+		})
+	})
+}
+
+func TestGoRendererProtoFieldType(t *testing.T) {
+	p := newTestPackage(t)
+	file := testProtos(t, p)
+	registry := p.mod.registry
+	user := file.Message(registry, "User")
+
+	tests := []struct {
+		field string
+		want  string
+	}{
+		{field: "id", want: "int64"},
+		{field: "role", want: "examplev1.User_Role"},
+		{field: "address", want: "*examplev1.User_Address"},
+		{field: "tags", want: "[]string"},
+		{field: "counters", want: "map[string]int64"},
+		{field: "avatar", want: "[]byte"},
+		{field: "score", want: "*int64"},
+		{field: "prev_status", want: "*examplev1.Status"},
+		{field: "signature", want: "[]byte"},
+		{field: "nick", want: "*wrapperspb.StringValue"},
+		{field: "created_at", want: "*timestamppb.Timestamp"},
+		{field: "ttl", want: "*durationpb.Duration"},
+		{field: "extra", want: "*anypb.Any"},
+		{field: "nothing", want: "*emptypb.Empty"},
+		{field: "meta", want: "*structpb.Struct"},
+		{field: "value", want: "*structpb.Value"},
+		{field: "mask", want: "*fieldmaskpb.FieldMask"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			r := p.Go(tt.field + ".go")
+			if got := r.ProtoFieldType(user.Field(registry, tt.field)).Impl(); got != tt.want {
+				t.Errorf("unexpected field type '%s', want '%s'", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGoRendererProtoOneofBranch(t *testing.T) {
+	p := newTestPackage(t)
+	file := testProtos(t, p)
+	registry := p.mod.registry
+	contact := file.Message(registry, "User").Field(registry, "contact").Type(registry).(*past.OneOf)
+
+	r := p.Go("oneof.go")
+	var got []string
+	for branch := range contact.Branches(registry) {
+		got = append(got, r.ProtoOneofBranch(branch).Impl())
+	}
+
+	want := "*examplev1.User_Email,*examplev1.User_Postal"
+	if strings.Join(got, ",") != want {
+		t.Errorf("unexpected branch types '%s', want '%s'", strings.Join(got, ","), want)
+	}
+}
+
+func TestGoRendererProtoNames(t *testing.T) {
+	p := newTestPackage(t)
+	file := testProtos(t, p)
+	registry := p.mod.registry
+	msg := file.Message(registry, "Collisions")
+	payload := msg.Field(registry, "payload")
+	branches := payload.Type(registry).(*past.OneOf)
+
+	tests := []struct {
+		field  past.FieldNode
+		name   string
+		getter string
+	}{
+		{field: msg.Field(registry, "reset"), name: "Reset_", getter: "GetReset_"},
+		{field: msg.Field(registry, "name"), name: "Name", getter: "GetName"},
+		{field: msg.Field(registry, "get_name"), name: "GetName_", getter: "GetGetName_"},
+		{field: msg.Field(registry, "descriptor"), name: "Descriptor_", getter: "GetDescriptor_"},
+		{field: msg.Field(registry, "foo2bar"), name: "Foo2Bar", getter: "GetFoo2Bar"},
+		{field: payload, name: "Payload", getter: "GetPayload"},
+		{field: branches.Branch(registry, "kind"), name: "Kind", getter: "GetKind"},
+		{field: branches.Branch(registry, "text"), name: "Text", getter: "GetText"},
+		{field: branches.Branch(registry, "v2kind"), name: "V2Kind", getter: "GetV2Kind"},
+	}
+
+	r := p.Go("names.go")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.ProtoField(tt.field); got != tt.name {
+				t.Errorf("unexpected field name '%s', want '%s'", got, tt.name)
+			}
+			if got := r.ProtoGetter(tt.field); got != tt.getter {
+				t.Errorf("unexpected getter name '%s', want '%s'", got, tt.getter)
+			}
+		})
+	}
+
+	if got := r.ProtoOneofIface(payload).String(); got != "examplev1.isCollisions_Payload" {
+		t.Errorf("unexpected oneof interface '%s'", got)
+	}
+	if got := r.ProtoFieldType(payload).Impl(); got != "examplev1.isCollisions_Payload" {
+		t.Errorf("unexpected oneof field type '%s'", got)
+	}
+	if got := r.ProtoOneofBranch(branches.Branch(registry, "kind")).Impl(); got != "*examplev1.Collisions_Kind_" {
+		t.Errorf("unexpected oneof branch wrapper '%s'", got)
+	}
+	if got := r.ProtoOneofBranch(branches.Branch(registry, "text")).Impl(); got != "*examplev1.Collisions_Text" {
+		t.Errorf("unexpected oneof branch wrapper '%s'", got)
+	}
+	if got := r.ProtoOneofBranch(branches.Branch(registry, "v2kind")).Impl(); got != "*examplev1.Collisions_V2Kind_" {
+		t.Errorf("unexpected oneof branch wrapper '%s'", got)
+	}
+	for typ := range msg.Types(registry) {
+		if v, ok := typ.(*past.Message); ok && v.Name() == "v2kind" {
+			if got := r.Proto(v).Impl(); got != "*examplev1.Collisions_V2Kind" {
+				t.Errorf("unexpected nested message type '%s'", got)
+			}
+		}
+	}
+}
+
+func TestGoRendererProtoMessageFields(t *testing.T) {
+	p := newTestPackage(t)
+	file := testProtos(t, p)
+
+	var got []string
+	for _, f := range p.Go("fields.go").protoMessageFields(file.Message(p.mod.registry, "Collisions")) {
+		got = append(got, f.Name())
+	}
+
+	want := "reset,name,get_name,descriptor,foo2bar,payload"
+	if strings.Join(got, ",") != want {
+		t.Errorf("unexpected fields '%s', want '%s'", strings.Join(got, ","), want)
+	}
+}
+
+func TestGoRendererProtoService(t *testing.T) {
+	p := newTestPackage(t)
+	file := testProtos(t, p)
+	registry := p.mod.registry
+	service := file.Service(registry, "UserService")
+
+	r := p.Go("grpc.go")
+	s := r.ProtoService(service)
+	for _, tt := range []struct {
+		got  ProtocType
+		want string
+	}{
+		{got: s.Client(), want: "examplev1.UserServiceClient"},
+		{got: s.NewClient(), want: "examplev1.NewUserServiceClient"},
+		{got: s.Server(), want: "examplev1.UserServiceServer"},
+		{got: s.UnimplementedServer(), want: "examplev1.UnimplementedUserServiceServer"},
+		{got: s.UnsafeServer(), want: "examplev1.UnsafeUserServiceServer"},
+		{got: s.RegisterServer(), want: "examplev1.RegisterUserServiceServer"},
+		{got: s.ServiceDesc(), want: "examplev1.UserService_ServiceDesc"},
+	} {
+		if tt.got.String() != tt.want {
+			t.Errorf("unexpected name '%s', want '%s'", tt.got, tt.want)
+		}
+	}
+
+	unary := r.ProtoMethod(service.Method(registry, "GetUser"))
+	if got := unary.FullMethodName().String(); got != "examplev1.UserService_GetUser_FullMethodName" {
+		t.Errorf("unexpected full method name '%s'", got)
+	}
+	if unary.IsStreaming() {
+		t.Error("GetUser must not be streaming")
+	}
+
+	for _, name := range []string{"ListUsers", "Upload", "Chat"} {
+		m := r.ProtoMethod(service.Method(registry, name))
+		if !m.IsStreaming() {
+			t.Errorf("%s must be streaming", name)
+		}
+		if got := m.ClientStream().String(); got != "examplev1.UserService_"+name+"Client" {
+			t.Errorf("unexpected client stream '%s'", got)
+		}
+		if got := m.ServerStream().String(); got != "examplev1.UserService_"+name+"Server" {
+			t.Errorf("unexpected server stream '%s'", got)
+		}
+	}
+
+	// Names with digits are cased the protoc-gen-go-grpc way.
+	auth := file.Service(registry, "auth2fa")
+	if got := r.ProtoService(auth).Client().String(); got != "examplev1.Auth2FaClient" {
+		t.Errorf("unexpected client '%s'", got)
+	}
+	check := r.ProtoMethod(auth.Method(registry, "check2fa"))
+	if got := check.FullMethodName().String(); got != "examplev1.Auth2Fa_Check2Fa_FullMethodName" {
+		t.Errorf("unexpected full method name '%s'", got)
+	}
+}
@@ -0,0 +1,214 @@
+package gogh
+
+import (
+	"strings"
+
+	"github.com/sirkon/errors"
+)
+
+// If renders if statement with the given condition and returns a renderer
+// of its branches. The condition is formatted the same way L does, inline
+// uniques like @v are visible in all branches:
+//
+//	r.If(`@v, ok := $m[$key]; ok`).Then(func(r *GoRenderer[T]) {
+//	    r.L(`return $v`)
+//	}).Else(func(r *GoRenderer[T]) {
+//	    r.L(`return $default`)
+//	})
+//
+// The closing brace is rendered right away, so there is no need to end
+// the chain with Else. Each branch is rendered within its own scope.
+func (r *GoRenderer[T]) If(cond string, a ...any) *GoIfRenderer[T] {
+	s := r.Scope()
+	s.L(`if `+cond+` {`, a...)
+	b := s.Z()
+	s.R(`}`)
+
+	return &GoIfRenderer[T]{
+		r: b,
+	}
+}
+
+type (
+	// GoIfRenderer renders a branch of if statement.
+	GoIfRenderer[T Importer] struct {
+		r *GoRenderer[T]
+	}
+
+	// GoIfElseRenderer renders else branches of if statement.
+	GoIfElseRenderer[T Importer] struct {
+		r *GoRenderer[T]
+	}
+)
+
+// Then renders the branch body.
+func (i *GoIfRenderer[T]) Then(f func(r *GoRenderer[T])) *GoIfElseRenderer[T] {
+	f(i.r.Scope())
+
+	return &GoIfElseRenderer[T]{
+		r: i.r,
+	}
+}
+
+// ElseIf renders else if branch with the given condition.
+func (i *GoIfElseRenderer[T]) ElseIf(cond string, a ...any) *GoIfRenderer[T] {
+	i.r.L(`} else if `+cond+` {`, a...)
+
+	return &GoIfRenderer[T]{
+		r: i.r,
+	}
+}
+
+// Else renders else branch.
+func (i *GoIfElseRenderer[T]) Else(f func(r *GoRenderer[T])) {
+	i.r.R(`} else {`)
+	f(i.r.Scope())
+}
+
+// Switch renders switch statement over the given expression and returns
+// a renderer of its cases. The expression is formatted the same way L
+// does and can be empty. Inline uniques are visible in all cases, so it
+// fits type switches well:
+//
+//	r.Switch(`@v := $x.(type)`).
+//	    Case(types.Typ[types.Int]).Then(func(r *GoRenderer[T]) {
+//	        r.L(`return $v`)
+//	    }).
+//	    Default(func(r *GoRenderer[T]) {
+//	        r.L(`return 0`)
+//	    })
+//
+// The closing brace is rendered right away. Each case is rendered within
+// its own scope.
+func (r *GoRenderer[T]) Switch(expr string, a ...any) *GoSwitchRenderer[T] {
+	s := r.Scope()
+	if expr == "" {
+		s.R(`switch {`)
+	} else {
+		s.L(`switch `+expr+` {`, a...)
+	}
+	b := s.Z()
+	s.R(`}`)
+
+	return &GoSwitchRenderer[T]{
+		r: b,
+	}
+}
+
+type (
+	// GoSwitchRenderer renders cases of switch statement.
+	GoSwitchRenderer[T Importer] struct {
+		r *GoRenderer[T]
+	}
+
+	// GoSwitchCaseRenderer renders a body of the switch case.
+	GoSwitchCaseRenderer[T Importer] struct {
+		r *GoRenderer[T]
+	}
+)
+
+// Case renders case clause with the given values. Strings are formatted
+// the same way L does, other values are rendered as if they were passed
+// as L arguments, meaning types.Type and past.Type are supported.
+func (s *GoSwitchRenderer[T]) Case(values ...any) *GoSwitchCaseRenderer[T] {
+	vals := make([]string, 0, len(values))
+	for _, v := range values {
+		switch vv := v.(type) {
+		case string:
+			vals = append(vals, s.r.S(vv))
+		default:
+			vals = append(vals, s.r.S("$0", vv))
+		}
+	}
+	s.r.L(`case $0:`, strings.Join(vals, ", "))
+
+	return &GoSwitchCaseRenderer[T]{
+		r: s.r,
+	}
+}
+
+// Then renders the case body.
+func (c *GoSwitchCaseRenderer[T]) Then(f func(r *GoRenderer[T])) *GoSwitchRenderer[T] {
+	f(c.r.Scope())
+
+	return &GoSwitchRenderer[T]{
+		r: c.r,
+	}
+}
+
+// Default renders default clause.
+func (s *GoSwitchRenderer[T]) Default(f func(r *GoRenderer[T])) {
+	s.r.R(`default:`)
+	f(s.r.Scope())
+}
+
+// Range renders for … range loop. Key and value variable names are
+// made unique with Uniq and are set into the body scope under the
+// given names. Empty key means no variables at all and empty value
+// means only key is taken. "_" is kept as is.
+//
+//	r.Range("i", "item", "$items").Body(func(r *GoRenderer[T]) {
+//	    r.L(`$dst[$i] = $item.ID`)
+//	})
+//
+// Produces something like
+//
+//	for i2, item := range items {
+//	    dst[i2] = item.ID
+//	}
+//
+// if the i was taken before.
+func (r *GoRenderer[T]) Range(key, value, expr string, a ...any) *GoRangeRenderer[T] {
+	if key == "" && value != "" {
+		panic(errors.New("range key must not be empty when value is set"))
+	}
+
+	return &GoRangeRenderer[T]{
+		r:     r,
+		key:   key,
+		value: value,
+		expr:  r.S(expr, a...),
+	}
+}
+
+// GoRangeRenderer renders a body of range loop.
+type GoRangeRenderer[T Importer] struct {
+	r     *GoRenderer[T]
+	key   string
+	value string
+	expr  string
+}
+
+// Body renders the loop body.
+func (l *GoRangeRenderer[T]) Body(f func(r *GoRenderer[T])) {
+	s := l.r.Scope()
+
+	var vars []string
+	for _, name := range []string{l.key, l.value} {
+		switch name {
+		case "":
+		case "_":
+			vars = append(vars, name)
+		default:
+			checkName("range variable", name)
+			v := s.Uniq(name)
+			s.Let(name, v)
+			vars = append(vars, v)
+		}
+	}
+
+	for len(vars) > 0 && vars[len(vars)-1] == "_" {
+		vars = vars[:len(vars)-1]
+	}
+
+	switch len(vars) {
+	case 0:
+		s.R(`for range ` + l.expr + ` {`)
+	case 1:
+		s.R(`for ` + vars[0] + ` := range ` + l.expr + ` {`)
+	default:
+		s.R(`for ` + vars[0] + `, ` + vars[1] + ` := range ` + l.expr + ` {`)
+	}
+	f(s.Scope())
+	s.R(`}`)
+}
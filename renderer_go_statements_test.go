@@ -0,0 +1,78 @@
+package gogh
+
+import (
+	"go/types"
+	"testing"
+)
+
+func TestGoRendererStatements(t *testing.T) {
+	r := newTestPackage(t).Go("statements.go")
+	r.F("sample")("m", "map[string]any", "keys", "[]string").Returns("int").Body(func(r *GoRenderer[*Imports]) {
+		r.Let("m", "m")
+		r.L(`var @res int`)
+		r.Range("i", "key", "keys").Body(func(r *GoRenderer[*Imports]) {
+			r.If(`@v, ok := $m[$key]; !ok`).Then(func(r *GoRenderer[*Imports]) {
+				r.L(`continue`)
+			}).ElseIf(`$v == nil`).Then(func(r *GoRenderer[*Imports]) {
+				r.L(`$res -= $i`)
+			}).Else(func(r *GoRenderer[*Imports]) {
+				r.Switch(`@x := $v.(type)`).
+					Case(types.Typ[types.Int]).Then(func(r *GoRenderer[*Imports]) {
+					r.L(`$res += $x`)
+				}).
+					Case("string", "[]byte").Then(func(r *GoRenderer[*Imports]) {
+					r.L(`$res--`)
+				}).
+					Default(func(r *GoRenderer[*Imports]) {
+						r.L(`$res++`)
+					})
+			})
+			r.L(`@v := $key`)
+			r.L(`_ = $v`)
+		})
+		r.Range("i", "_", "keys").Body(func(r *GoRenderer[*Imports]) {
+			r.L(`$res += $i`)
+		})
+		r.Range("_", "", "keys").Body(func(r *GoRenderer[*Imports]) {})
+		r.Switch("").Case("$res > 0").Then(func(r *GoRenderer[*Imports]) {
+			r.L(`return $res`)
+		})
+		r.L(`return 0`)
+	})
+
+	const want = `func sample(m map[string]any, keys []string) int {
+	var res int
+	for i, key := range keys {
+		if v, ok := m[key]; !ok {
+			continue
+		} else if v == nil {
+			res -= i
+		} else {
+			switch x := v.(type) {
+			case int:
+				res += x
+			case string, []byte:
+				res--
+			default:
+				res++
+			}
+		}
+		v := key
+		_ = v
+	}
+	for i := range keys {
+		res += i
+	}
+	for range keys {
+	}
+	switch {
+	case res > 0:
+		return res
+	}
+	return 0
+}
+`
+	if got := renderedCode(t, r); got != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,181 @@
+package gogh
+
+import (
+	"go/ast"
+	"go/parser"
+	"strings"
+
+	"github.com/sirkon/errors"
+)
+
+// Struct returns a renderer of a struct type definition.
+//
+// Usage example:
+//
+//	r.Struct("User").
+//	    Doc("User represents a registered user.").
+//	    Embed("$sync.Mutex").
+//	    Field("ID", "int64", JSONTag("id"), DBTag("id")).
+//	    Field("Name", types.Typ[types.String], JSONTag("name", TagOmitEmpty)).
+//	    Render()
+//
+// Produces
+//
+//	// User represents a registered user.
+//	type User struct {
+//	    sync.Mutex
+//	    ID   int64  `json:"id" db:"id"`
+//	    Name string `json:"name,omitempty"`
+//	}
+//
+// Field names, including implicit names of embedded fields, must be unique.
+func (r *GoRenderer[T]) Struct(name string) *GoStructRenderer[T] {
+	checkName("struct", name)
+
+	// Field names are taken from a dedicated scope, they do not clash
+	// with names of the file.
+	names := r.Scope()
+	names.uniqs = map[string]struct{}{}
+
+	return &GoStructRenderer[T]{
+		r:     r,
+		name:  name,
+		names: names,
+	}
+}
+
+type (
+	// GoStructRenderer renders a struct type definition.
+	GoStructRenderer[T Importer] struct {
+		r       *GoRenderer[T]
+		name    string
+		names   *GoRenderer[T]
+		doc     []string
+		tparams [][2]string
+		fields  []structField
+	}
+
+	structField struct {
+		name string
+		typ  string
+		tags string
+	}
+)
+
+// Doc adds a paragraph of the struct doc comment. The text is
+// formatted and wrapped the same way GoRenderer.Doc does.
+func (s *GoStructRenderer[T]) Doc(text string, a ...any) *GoStructRenderer[T] {
+	if len(s.doc) > 0 {
+		s.doc = append(s.doc, "")
+	}
+	s.doc = append(s.doc, s.r.docLines(text, a...)...)
+	return s
+}
+
+// TypeParam adds a type parameter with the given constraint. The
+// constraint is a string, fmt.Stringer, types.Type or past.Type.
+func (s *GoStructRenderer[T]) TypeParam(name string, constraint any) *GoStructRenderer[T] {
+	checkName("type parameter", name)
+	for _, p := range s.tparams {
+		if p[0] == name {
+			panic(errors.Newf("type parameter '%s' has been defined already", name))
+		}
+	}
+
+	s.tparams = append(s.tparams, [2]string{name, s.r.typeString(constraint)})
+	return s
+}
+
+// Field adds a field of the given type. The typ is a string, fmt.Stringer,
+// types.Type or past.Type.
+func (s *GoStructRenderer[T]) Field(name string, typ any, tags ...StructTag) *GoStructRenderer[T] {
+	checkName("struct field", name)
+	s.takeFieldName(name)
+	s.fields = append(s.fields, structField{
+		name: name,
+		typ:  s.r.typeString(typ),
+		tags: renderStructTags(tags),
+	})
+
+	return s
+}
+
+// Embed adds an embedded field of the given type. The typ is the
+// same as for Field.
+func (s *GoStructRenderer[T]) Embed(typ any, tags ...StructTag) *GoStructRenderer[T] {
+	t := s.r.typeString(typ)
+	s.takeFieldName(embeddedFieldName(t))
+	s.fields = append(s.fields, structField{
+		typ:  t,
+		tags: renderStructTags(tags),
+	})
+
+	return s
+}
+
+// Render renders the struct definition.
+func (s *GoStructRenderer[T]) Render() {
+	r := s.r
+	for _, line := range s.doc {
+		r.R(docCommentLine(line))
+	}
+
+	var tparams string
+	if len(s.tparams) > 0 {
+		parts := make([]string, 0, len(s.tparams))
+		for _, p := range s.tparams {
+			parts = append(parts, p[0]+" "+p[1])
+		}
+		tparams = "[" + strings.Join(parts, ", ") + "]"
+	}
+
+	if len(s.fields) == 0 {
+		r.R("type " + s.name + tparams + " struct{}")
+		return
+	}
+
+	r.R("type " + s.name + tparams + " struct {")
+	for _, f := range s.fields {
+		line := f.typ
+		if f.name != "" {
+			line = f.name + " " + line
+		}
+		if f.tags != "" {
+			line += " " + f.tags
+		}
+		r.R("    " + line)
+	}
+	r.R("}")
+}
+
+func (s *GoStructRenderer[T]) takeFieldName(name string) {
+	if s.names.Uniq(name) != name {
+		panic(errors.Newf("struct %s field '%s' has been defined already", s.name, name))
+	}
+}
+
+// embeddedFieldName computes an implicit name of an embedded field of the given type.
+func embeddedFieldName(typ string) string {
+	expr, err := parser.ParseExpr(typ)
+	if err != nil {
+		panic(errors.Wrapf(err, "parse embedded type '%s'", typ))
+	}
+
+	if v, ok := expr.(*ast.StarExpr); ok {
+		expr = v.X
+	}
+	switch v := expr.(type) {
+	case *ast.IndexExpr:
+		expr = v.X
+	case *ast.IndexListExpr:
+		expr = v.X
+	}
+	switch v := expr.(type) {
+	case *ast.Ident:
+		return v.Name
+	case *ast.SelectorExpr:
+		return v.Sel.Name
+	default:
+		panic(errors.Newf("type '%s' cannot be embedded", typ))
+	}
+}
@@ -0,0 +1,88 @@
+package gogh
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/sirkon/errors"
+)
+
+// TagOmitEmpty is an option of json, yaml and alike tags.
+const TagOmitEmpty = "omitempty"
+
+// StructTag represents a single key:"value" pair of a struct field tag.
+type StructTag struct {
+	key  string
+	name string
+	opts []string
+}
+
+// Tag creates a struct field tag with the given key, name and options.
+// The value of the tag will be the name followed by comma separated
+// options:
+//
+//	Tag("json", "id", TagOmitEmpty) // json:"id,omitempty"
+func Tag(key, name string, opts ...string) StructTag {
+	if key == "" {
+		panic(errors.New("tag key must not be empty"))
+	}
+	for _, c := range key {
+		if c <= ' ' || c == ':' || c == '"' || c == '`' || c == 0x7f {
+			panic(errors.Newf("invalid tag key '%s'", key))
+		}
+	}
+	for _, v := range append([]string{name}, opts...) {
+		if strings.ContainsAny(v, "`,") {
+			panic(errors.Newf("tag %s value part must not contain '`' or ',', got '%s'", key, v))
+		}
+	}
+
+	return StructTag{
+		key:  key,
+		name: name,
+		opts: opts,
+	}
+}
+
+// JSONTag creates json tag.
+func JSONTag(name string, opts ...string) StructTag {
+	return Tag("json", name, opts...)
+}
+
+// YAMLTag creates yaml tag.
+func YAMLTag(name string, opts ...string) StructTag {
+	return Tag("yaml", name, opts...)
+}
+
+// DBTag creates db tag.
+func DBTag(name string, opts ...string) StructTag {
+	return Tag("db", name, opts...)
+}
+
+// String renders the tag as key:"value".
+func (t StructTag) String() string {
+	value := t.name
+	if len(t.opts) > 0 {
+		value += "," + strings.Join(t.opts, ",")
+	}
+
+	return t.key + ":" + strconv.Quote(value)
+}
+
+func renderStructTags(tags []StructTag) string {
+	if len(tags) == 0 {
+		return ""
+	}
+
+	keys := map[string]struct{}{}
+	parts := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if _, ok := keys[tag.key]; ok {
+			panic(errors.Newf("duplicate tag key '%s'", tag.key))
+		}
+		keys[tag.key] = struct{}{}
+		parts = append(parts, tag.String())
+	}
+
+	return "`" + strings.Join(parts, " ") + "`"
+}
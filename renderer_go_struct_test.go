@@ -0,0 +1,94 @@
+package gogh
+
+import (
+	"go/types"
+	"testing"
+)
+
+func TestGoRendererStruct(t *testing.T) {
+	src := typesOf(t, `package source
+
+type Base struct{}
+`)
+
+	r := newTestPackage(t).Go("struct.go")
+	r.Imports().Add("sync").Ref("sync")
+	r.Struct("User").
+		Doc("User represents a registered $0.", "user").
+		TypeParam("T", "any").
+		Embed(types.NewPointer(src.Scope().Lookup("Base").Type())).
+		Embed("$sync.Mutex").
+		Field("ID", "int64", JSONTag("id"), DBTag("id")).
+		Field("Name", types.Typ[types.String], JSONTag("name", TagOmitEmpty), YAMLTag("name", "flow")).
+		Field("Extra", "T", JSONTag("-")).
+		Render()
+	r.N()
+	r.Struct("Empty").Render()
+
+	const want = `// User represents a registered user.
+type User[T any] struct {
+	*source.Base
+	sync.Mutex
+	ID    int64  ` + "`" + `json:"id" db:"id"` + "`" + `
+	Name  string ` + "`" + `json:"name,omitempty" yaml:"name,flow"` + "`" + `
+	Extra T      ` + "`" + `json:"-"` + "`" + `
+}
+
+type Empty struct{}
+`
+	if got := renderedCode(t, r); got != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestGoRendererStructFieldNamespace(t *testing.T) {
+	r := newTestPackage(t).Go("struct.go")
+	r.Uniq("id")
+	r.Struct("User").Field("id", "int").Render()
+
+	const want = `type User struct {
+	id int
+}
+`
+	if got := renderedCode(t, r); got != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestGoRendererStructDuplicates(t *testing.T) {
+	tests := []struct {
+		name string
+		f    func(s *GoStructRenderer[*Imports])
+	}{
+		{
+			name: "field",
+			f: func(s *GoStructRenderer[*Imports]) {
+				s.Field("ID", "int").Field("ID", "string")
+			},
+		},
+		{
+			name: "embedded",
+			f: func(s *GoStructRenderer[*Imports]) {
+				s.Field("Mutex", "int").Embed("*sync.Mutex")
+			},
+		},
+		{
+			name: "tag-key",
+			f: func(s *GoStructRenderer[*Imports]) {
+				s.Field("ID", "int", JSONTag("id"), JSONTag("ID"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("panic expected")
+				}
+			}()
+
+			tt.f(newTestPackage(t).Go("struct.go").Struct("S"))
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package gogh
+
+import (
+	"go/types"
+	"testing"
+
+	"github.com/sirkon/gogh/internal/consts"
+)
+
+func TestGoRendererZero(t *testing.T) {
+	pkg := typesOf(t, `package source
+
+import (
+	"bytes"
+	"time"
+	"unsafe"
+)
+
+type (
+	Num  int
+	Flag bool
+	Str  string
+	Point struct{ X, Y int }
+	Arr  [2]Point
+	Iface interface{ M() }
+	Err  interface {
+		error
+		Code() int
+	}
+	Gen[T any] struct{ v T }
+
+	PointAlias = Point
+	MapAlias   = map[string]int
+)
+
+var (
+	num   Num
+	flag  Flag
+	str   Str
+	point Point
+	arr   Arr
+	iface Iface
+	err   Err
+	gen   Gen[int]
+	palias PointAlias
+	malias MapAlias
+	anon  struct {
+		D time.Duration `+"`json:\"d\"`"+`
+	}
+	buf   bytes.Buffer
+	ptr   *Point
+	slice []Point
+	ch    <-chan int
+	fn    func() error
+	uptr  unsafe.Pointer
+	r     rune
+)
+
+func Generic[T any]() {}
+`)
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "num", want: "0"},
+		{name: "flag", want: "false"},
+		{name: "str", want: `""`},
+		{name: "point", want: "source.Point{}"},
+		{name: "arr", want: "source.Arr{}"},
+		{name: "iface", want: "nil"},
+		{name: "err", want: "nil"},
+		{name: "gen", want: "source.Gen[int]{}"},
+		{name: "palias", want: "source.PointAlias{}"},
+		{name: "malias", want: "nil"},
+		{name: "anon", want: "struct{D time.Duration \"json:\\\"d\\\"\"}{}"},
+		{name: "buf", want: "bytes.Buffer{}"},
+		{name: "ptr", want: "nil"},
+		{name: "slice", want: "nil"},
+		{name: "ch", want: "nil"},
+		{name: "fn", want: "nil"},
+		{name: "uptr", want: "nil"},
+		{name: "r", want: "0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestPackage(t).Go("zero.go")
+			if got := r.Zero(pkg.Scope().Lookup(tt.name).Type()); got != tt.want {
+				t.Errorf("unexpected zero value '%s', want '%s'", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("type-param", func(t *testing.T) {
+		r := newTestPackage(t).Go("zero.go")
+		sig := pkg.Scope().Lookup("Generic").Type().(*types.Signature)
+		if got := r.Zero(sig.TypeParams().At(0)); got != "*new(T)" {
+			t.Errorf("unexpected zero value '%s', want '*new(T)'", got)
+		}
+	})
+
+	t.Run("last-error", func(t *testing.T) {
+		r := newTestPackage(t).Go("zero.go")
+		if got := zeroValueOfTypesType(r, pkg.Scope().Lookup("err").Type(), true); got != consts.ErrorTypeZeroSign {
+			t.Errorf("unexpected zero value '%s', want '%s'", got, consts.ErrorTypeZeroSign)
+		}
+	})
+
+	t.Run("imports", func(t *testing.T) {
+		r := newTestPackage(t).Go("zero.go")
+		r.Zero(pkg.Scope().Lookup("anon").Type())
+		if _, ok := r.imports.Imports().pkgs["time"]; !ok {
+			t.Error("time package must be imported")
+		}
+	})
+}
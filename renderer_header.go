@@ -0,0 +1,118 @@
+package gogh
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+)
+
+// CommentStyle describes comment syntax of a rendered file. Comments are
+// rendered line by line: Start and End (if any) are put around each line.
+type CommentStyle struct {
+	Start string
+	End   string
+}
+
+// Comment styles of widespread file formats.
+var (
+	// CommentHash is for shell scripts, YAML, TOML, Makefiles, etc.
+	CommentHash = CommentStyle{Start: "#"}
+	// CommentDashes is for SQL.
+	CommentDashes = CommentStyle{Start: "--"}
+	// CommentSlashes is for protobuf, Go and C-like languages.
+	CommentSlashes = CommentStyle{Start: "//"}
+	// CommentXML is for XML, HTML and Markdown.
+	CommentXML = CommentStyle{Start: "<!--", End: "-->"}
+)
+
+// commentStyles comment styles by file extensions or by file names for
+// files without extensions.
+var commentStyles = map[string]CommentStyle{
+	".sh":           CommentHash,
+	".bash":         CommentHash,
+	".zsh":          CommentHash,
+	".yaml":         CommentHash,
+	".yml":          CommentHash,
+	".toml":         CommentHash,
+	".py":           CommentHash,
+	".rb":           CommentHash,
+	".mk":           CommentHash,
+	".conf":         CommentHash,
+	".env":          CommentHash,
+	".gitignore":    CommentHash,
+	".dockerignore": CommentHash,
+	"Makefile":      CommentHash,
+	"Dockerfile":    CommentHash,
+	".sql":          CommentDashes,
+	".lua":          CommentDashes,
+	".proto":        CommentSlashes,
+	".go":           CommentSlashes,
+	".js":           CommentSlashes,
+	".ts":           CommentSlashes,
+	".c":            CommentSlashes,
+	".h":            CommentSlashes,
+	".cpp":          CommentSlashes,
+	".java":         CommentSlashes,
+	".kt":           CommentSlashes,
+	".rs":           CommentSlashes,
+	".swift":        CommentSlashes,
+	".html":         CommentXML,
+	".xml":          CommentXML,
+	".svg":          CommentXML,
+	".md":           CommentXML,
+}
+
+// commentStyleOf infers comment style of the file by its name.
+func commentStyleOf(name string) (CommentStyle, bool) {
+	base := filepath.Base(name)
+	if style, ok := commentStyles[base]; ok {
+		return style, true
+	}
+
+	style, ok := commentStyles[strings.ToLower(filepath.Ext(base))]
+	return style, ok
+}
+
+// fileHeader collects header comment of a rendered file. License goes
+// first, other lines go after it, Go build constraint goes last.
+type fileHeader struct {
+	license []string
+	lines   []string
+	build   string
+}
+
+func (h *fileHeader) empty() bool {
+	return len(h.license) == 0 && len(h.lines) == 0 && h.build == ""
+}
+
+// render renders header sections with the given comment style, each
+// section is followed by an empty line.
+func (h *fileHeader) render(style CommentStyle) []byte {
+	var buf bytes.Buffer
+	for _, section := range [][]string{h.license, h.lines} {
+		if len(section) == 0 {
+			continue
+		}
+
+		for _, line := range section {
+			buf.WriteString(style.Start)
+			if line != "" {
+				buf.WriteByte(' ')
+				buf.WriteString(line)
+			}
+			if style.End != "" {
+				buf.WriteByte(' ')
+				buf.WriteString(style.End)
+			}
+			buf.WriteByte('\n')
+		}
+		buf.WriteByte('\n')
+	}
+	if h.build != "" {
+		buf.WriteString("//go:build ")
+		buf.WriteString(h.build)
+		buf.WriteString("\n\n")
+	}
+
+	return buf.Bytes()
+}
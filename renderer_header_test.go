@@ -0,0 +1,176 @@
+package gogh
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestRawRendererHeader(t *testing.T) {
+	type test struct {
+		name    string
+		opts    []RendererOption
+		content string
+		want    string
+		wantErr string
+	}
+
+	license := License("Copyright 2026 Example Inc.\nSPDX-License-Identifier: MIT")
+	tests := []test{
+		{
+			name:    "config.yaml",
+			opts:    []RendererOption{Autogen("gen"), license, WithFormatter(YAMLFmt)},
+			content: "b: 1\na: 2\n",
+			want: `# Copyright 2026 Example Inc.
+# SPDX-License-Identifier: MIT
+
+# Code generated by gen version (devel). DO NOT EDIT.
+
+a: 2
+b: 1
+`,
+		},
+		{
+			name:    "schema.sql",
+			opts:    []RendererOption{Autogen("gen")},
+			content: "SELECT 1;\n",
+			want: `-- Code generated by gen version (devel). DO NOT EDIT.
+
+SELECT 1;
+`,
+		},
+		{
+			name:    "run.sh",
+			opts:    []RendererOption{Autogen("gen")},
+			content: "#!/bin/sh\n\necho hello\n",
+			want: `#!/bin/sh
+
+# Code generated by gen version (devel). DO NOT EDIT.
+
+echo hello
+`,
+		},
+		{
+			name:    "README.md",
+			opts:    []RendererOption{Autogen("gen")},
+			content: "# Title\n",
+			want: `<!-- Code generated by gen version (devel). DO NOT EDIT. -->
+
+# Title
+`,
+		},
+		{
+			name:    "data.custom",
+			opts:    []RendererOption{Autogen("gen"), WithCommentStyle(CommentSlashes)},
+			content: "data\n",
+			want: `// Code generated by gen version (devel). DO NOT EDIT.
+
+data
+`,
+		},
+		{
+			name:    "data.json",
+			opts:    []RendererOption{Autogen("gen")},
+			content: "{}\n",
+			wantErr: "unknown comment syntax",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestPackage(t)
+
+			r := p.Raw(tt.name, tt.opts...)
+			r.Put([]byte(tt.content))
+			err := r.render()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error containing '%s' expected, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			data, err := os.ReadFile(r.fullname)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("unexpected output:\n%s\nwant:\n%s", data, tt.want)
+			}
+		})
+	}
+}
+
+func TestProtoRendererHeader(t *testing.T) {
+	p := newTestPackage(t)
+
+	r := p.Proto("header.proto", Autogen("gen"))
+	r.L(`message Empty {}`)
+	if err := r.render(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(r.fullname)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "// Code generated by gen version (devel). DO NOT EDIT.\n\nsyntax = \"proto3\";\n"; !strings.HasPrefix(string(data), want) {
+		t.Errorf("unexpected output:\n%s", data)
+	}
+}
+
+func TestGoRendererHeader(t *testing.T) {
+	p := newTestPackage(t)
+	p.mod.license = "Copyright 2026 Example Inc."
+
+	render := func(name string, opts ...RendererOption) string {
+		r := p.Go(name, opts...)
+		r.L(`var _ = 1`)
+		if err := r.render(); err != nil {
+			t.Fatal(err)
+		}
+
+		data, err := os.ReadFile(r.path())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	want := `// Copyright 2026 Example Inc.
+
+// Code generated by gen version (devel). DO NOT EDIT.
+
+//go:build linux && !cgo
+
+package test
+
+var _ = 1
+`
+	if got := render("default.go", GoBuild("linux&&!cgo"), Autogen("gen")); got != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", got, want)
+	}
+
+	want = `// SPDX-FileCopyrightText: 2026 Example Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+var _ = 1
+`
+	if got := render("spdx.go", SPDX("Apache-2.0", "Example Inc.", 2026)); got != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestGoBuildInvalid(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("panic expected")
+		}
+	}()
+	GoBuild("linux &&")
+}
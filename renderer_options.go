@@ -1,9 +1,12 @@
 package gogh
 
 import (
-	"bytes"
+	"go/build/constraint"
+	"maps"
 	"os"
-	"runtime/debug"
+	"reflect"
+	"strconv"
+	"strings"
 
 	"github.com/sirkon/errors"
 	"github.com/sirkon/message"
@@ -14,15 +17,22 @@ type renderingOptionsHandler interface {
 	path() string
 	// localname local file name within a module of a file to be written
 	localPath() string
-	// comment file comment writer
-	comment() *bytes.Buffer
+	// root module root directory
+	root() string
+	// header file header comment
+	header() *fileHeader
+	// setCommentStyle set comment syntax of the file
+	setCommentStyle(style CommentStyle)
 	// setVals set rendering context values
 	setVals(vals map[string]any)
+	// setFormatter set formatter of the file
+	setFormatter(f Formatter)
 }
 
 var (
 	_ renderingOptionsHandler = &GoRenderer[*Imports]{}
 	_ renderingOptionsHandler = &RawRenderer{}
+	_ renderingOptionsHandler = &rendererOptionsFilter{}
 )
 
 // RendererOption an option to be applied before the rendering
@@ -45,19 +55,61 @@ func Shy(r renderingOptionsHandler) bool {
 }
 
 // Autogen puts header `Code generated by <app name> version vX.Y.Z. DO NOT EDIT.`
+//
+// Raw files get it with their comment syntax, see WithCommentStyle.
 func Autogen(appname string) RendererOption {
+	return autogen(appname, nil)
+}
+
+// License puts the given text as a header comment of the file. It goes
+// above any other header comment. Multiline texts are supported.
+func License(text string) RendererOption {
 	return func(r renderingOptionsHandler) bool {
-		r.comment().WriteString("// Code generated by ")
-		r.comment().WriteString(appname)
-		r.comment().WriteString(" version ")
-		info, ok := debug.ReadBuildInfo()
-		if !ok || info.Main.Version == "" {
-			r.comment().WriteString("(devel)")
-		} else {
-			r.comment().WriteString(info.Main.Version)
-		}
-		r.comment().WriteString(". DO NOT EDIT.\n")
+		lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+		r.header().license = append(r.header().license, lines...)
+		return true
+	}
+}
+
+// SPDX puts SPDX license header:
+//
+//	// SPDX-FileCopyrightText: 2026 Example Inc.
+//	// SPDX-License-Identifier: Apache-2.0
+func SPDX(id, holder string, year int) RendererOption {
+	return License(
+		"SPDX-FileCopyrightText: " + strconv.Itoa(year) + " " + holder + "\n" +
+			"SPDX-License-Identifier: " + id,
+	)
+}
+
+// GoBuild puts //go:build line with the given constraint expression.
+// It goes after header comments, as Go requires. It is for Go files only.
+//
+// It panics if the expression is not a valid build constraint.
+func GoBuild(expr string) RendererOption {
+	x, err := constraint.Parse("//go:build " + expr)
+	if err != nil {
+		panic(errors.Wrapf(err, "parse build constraint %q", expr))
+	}
+
+	return func(r renderingOptionsHandler) bool {
+		r.header().build = x.String()
+		return true
+	}
+}
 
+// WithCommentStyle sets comment syntax of the raw file header. It is
+// inferred from the file extension by default:
+//
+//   - CommentHash for .sh, .yaml, .toml, Makefile, etc.
+//   - CommentDashes for .sql.
+//   - CommentSlashes for .proto, .js, etc.
+//   - CommentXML for .html, .xml and .md.
+//
+// Go files always use // comments.
+func WithCommentStyle(style CommentStyle) RendererOption {
+	return func(r renderingOptionsHandler) bool {
+		r.setCommentStyle(style)
 		return true
 	}
 }
@@ -79,3 +131,118 @@ func WithValue(name string, value any) RendererOption {
 		return true
 	}
 }
+
+// Without opts out the file of default renderer options of the same kind
+// as the given one, see WithDefaultRendererOptions. Options are compared by
+// their kind, not by their values, so
+//
+//	pkg.Go("file.go", gogh.Without(gogh.Autogen("")))
+//
+// removes any Autogen default. License and SPDX are of the same kind.
+func Without(opt RendererOption) RendererOption {
+	return func(r renderingOptionsHandler) bool {
+		if f, ok := r.(*rendererOptionsFilter); ok {
+			f.skip[rendererOptionKind(opt)] = struct{}{}
+		}
+		return true
+	}
+}
+
+// rendererOptions merges default options with options of a file. Defaults
+// go first. Options that make sense once per file replace defaults of the
+// same kind: Autogen and AutogenInputs, License and SPDX, GoBuild,
+// WithFormatter and WithCommentStyle. Other options are appended to
+// defaults, values of WithValue and WithValues are merged with file ones
+// taking precedence, see rendererValues. Defaults excluded with Without are
+// dropped.
+func rendererOptions(defaults, opts []RendererOption) []RendererOption {
+	if len(defaults) == 0 {
+		return opts
+	}
+
+	filter := &rendererOptionsFilter{
+		skip: map[uintptr]struct{}{},
+	}
+	without := rendererOptionKind(Without(nil))
+
+	var own []RendererOption
+	for _, opt := range opts {
+		kind := rendererOptionKind(opt)
+		if kind == without {
+			opt(filter)
+			continue
+		}
+
+		if _, ok := singleRendererOptions[kind]; ok {
+			filter.skip[kind] = struct{}{}
+		}
+		own = append(own, opt)
+	}
+
+	res := make([]RendererOption, 0, len(defaults)+len(own))
+	for _, opt := range defaults {
+		if _, ok := filter.skip[rendererOptionKind(opt)]; !ok {
+			res = append(res, opt)
+		}
+	}
+
+	return append(res, own...)
+}
+
+// singleRendererOptions kinds of options which make sense once per file.
+var singleRendererOptions = map[uintptr]struct{}{
+	rendererOptionKind(Autogen("")):                   {},
+	rendererOptionKind(License("")):                   {},
+	rendererOptionKind(GoBuild("ignore")):             {},
+	rendererOptionKind(WithFormatter(nil)):            {},
+	rendererOptionKind(WithCommentStyle(CommentHash)): {},
+}
+
+// rendererValues sets values of WithValue and WithValues options into the
+// renderer and returns the rest of options. Unlike other options these ones
+// are applied at the renderer creation, as values must be available before
+// anything is rendered. Values are merged, later options override values
+// of earlier ones with the same name.
+func rendererValues(r renderingOptionsHandler, opts []RendererOption) []RendererOption {
+	value := rendererOptionKind(WithValue("", nil))
+	values := rendererOptionKind(WithValues(nil))
+
+	vals := &rendererOptionsFilter{
+		vals: map[string]any{},
+	}
+	var res []RendererOption
+	for _, opt := range opts {
+		switch rendererOptionKind(opt) {
+		case value, values:
+			opt(vals)
+		default:
+			res = append(res, opt)
+		}
+	}
+	if len(vals.vals) > 0 {
+		r.setVals(vals.vals)
+	}
+
+	return res
+}
+
+// rendererOptionKind returns code pointer of the option, which is shared
+// by all options made by the same constructor.
+func rendererOptionKind(opt RendererOption) uintptr {
+	return reflect.ValueOf(opt).Pointer()
+}
+
+// rendererOptionsFilter collects options excluded with Without and values
+// of WithValue and WithValues.
+type rendererOptionsFilter struct {
+	skip map[uintptr]struct{}
+	vals map[string]any
+}
+
+func (f *rendererOptionsFilter) path() string                       { return "" }
+func (f *rendererOptionsFilter) localPath() string                  { return "" }
+func (f *rendererOptionsFilter) root() string                       { return "" }
+func (f *rendererOptionsFilter) header() *fileHeader                { return &fileHeader{} }
+func (f *rendererOptionsFilter) setCommentStyle(style CommentStyle) {}
+func (f *rendererOptionsFilter) setVals(vals map[string]any)        { maps.Copy(f.vals, vals) }
+func (f *rendererOptionsFilter) setFormatter(formatter Formatter)   {}
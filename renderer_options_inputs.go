@@ -0,0 +1,195 @@
+package gogh
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/sirkon/errors"
+	"github.com/sirkon/message"
+)
+
+// AutogenInputs is Autogen which also records the generator command line and
+// a hash of the given inputs into the header:
+//
+//	// Code generated by app version v1.2.3. DO NOT EDIT.
+//	// Command: app -proto api.proto
+//	// Inputs: api.proto, config.yaml
+//	// Inputs hash: sha256:3f2a…
+//
+// Inputs are paths of files or directories relative to the current
+// directory, contents of directories are hashed recursively. Paths are
+// recorded relative to the module root. The hash also covers the generator
+// version, its executable and arguments. The file is not rewritten if it
+// exists and has the same hash in its header.
+//
+// AutogenInputs is of the same kind as Autogen, see Without.
+func AutogenInputs(appname string, inputs ...string) RendererOption {
+	return autogen(appname, &autogenInputs{
+		args:   os.Args[1:],
+		inputs: inputs,
+	})
+}
+
+type autogenInputs struct {
+	args   []string
+	inputs []string
+}
+
+func autogen(appname string, in *autogenInputs) RendererOption {
+	return func(r renderingOptionsHandler) bool {
+		lines := []string{"Code generated by " + appname + " version " + autogenVersion() + ". DO NOT EDIT."}
+		if in != nil {
+			keys := append([]string{appname}, generatorKeys(in.args)...)
+			hash, err := inputsHash(r.root(), keys, in.inputs)
+			if err != nil {
+				panic(errors.Wrap(err, "compute inputs hash"))
+			}
+
+			hashLine := "Inputs hash: " + hash
+			if fileHasHeaderLine(r.path(), hashLine) {
+				message.Info("inputs of", r.localPath(), "have not changed, skipping")
+				return false
+			}
+
+			lines = append(lines, "Command: "+commandLine(appname, in.args))
+			if len(in.inputs) > 0 {
+				inputs := make([]string, 0, len(in.inputs))
+				for _, input := range in.inputs {
+					inputs = append(inputs, moduleRelPath(r.root(), input))
+				}
+				lines = append(lines, "Inputs: "+strings.Join(inputs, ", "))
+			}
+			lines = append(lines, hashLine)
+		}
+		r.header().lines = append(r.header().lines, lines...)
+
+		return true
+	}
+}
+
+func autogenVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok || info.Main.Version == "" {
+		return "(devel)"
+	}
+
+	return info.Main.Version
+}
+
+// generatorKeys returns hash keys of the generator run: its version, hash
+// of its executable, as development builds share the version, and the
+// given arguments.
+func generatorKeys(args []string) []string {
+	keys := []string{autogenVersion()}
+	if exe, err := os.Executable(); err == nil {
+		keys = append(keys, fileHash(exe))
+	}
+
+	return append(keys, args...)
+}
+
+// inputsHash computes sha256 of the given keys and contents of input files.
+// Input files are hashed along with their paths relative to the module
+// root, so the hash does not depend on the module location.
+func inputsHash(root string, keys []string, inputs []string) (string, error) {
+	h := sha256.New()
+	for _, key := range keys {
+		writeHashPart(h, key)
+	}
+
+	files := map[string]string{}
+	for _, input := range inputs {
+		err := filepath.WalkDir(input, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() {
+				files[moduleRelPath(root, path)] = path
+			}
+			return nil
+		})
+		if err != nil {
+			return "", errors.Wrapf(err, "look for input files in %s", input)
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		data, err := os.ReadFile(files[name])
+		if err != nil {
+			return "", errors.Wrap(err, "read input file")
+		}
+
+		writeHashPart(h, name)
+		writeHashPart(h, string(data))
+	}
+
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// moduleRelPath returns the path relative to the module root in the slash
+// separated form. The path is returned as is if it cannot be made relative.
+func moduleRelPath(root string, path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	rel, err := filepath.Rel(root, abs)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+
+	return filepath.ToSlash(rel)
+}
+
+// writeHashPart writes length prefixed data, so parts boundaries
+// make a difference.
+func writeHashPart(w io.Writer, data string) {
+	_, _ = io.WriteString(w, strconv.Itoa(len(data)))
+	_, _ = io.WriteString(w, ":")
+	_, _ = io.WriteString(w, data)
+}
+
+// fileHasHeaderLine checks if the given line is among the leading comment
+// lines of the file.
+func fileHasHeaderLine(path string, line string) bool {
+	file, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	// Header lines may go after the shebang and the license, so look
+	// through a few dozens of lines.
+	s := bufio.NewScanner(file)
+	for i := 0; i < 64 && s.Scan(); i++ {
+		if strings.Contains(s.Text(), line) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func commandLine(appname string, args []string) string {
+	parts := []string{appname}
+	for _, arg := range args {
+		if arg == "" || strings.ContainsAny(arg, " \t\n\"'\\$`") {
+			arg = strconv.Quote(arg)
+		}
+		parts = append(parts, arg)
+	}
+
+	return strings.Join(parts, " ")
+}
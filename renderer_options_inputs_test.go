@@ -0,0 +1,79 @@
+package gogh
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAutogenInputs(t *testing.T) {
+	p := newTestPackage(t)
+
+	input := filepath.Join(p.mod.root, "config.yaml")
+	if err := os.WriteFile(input, []byte("a: 1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	render := func(content string) string {
+		t.Helper()
+
+		delete(p.mod.raws, "query.sql")
+		r := p.Raw("query.sql", autogen("gen", &autogenInputs{
+			args:   []string{"-config", input, "-name", "two words"},
+			inputs: []string{input},
+		}))
+		r.R(content)
+		if err := r.render(); err != nil {
+			t.Fatal(err)
+		}
+
+		data, err := os.ReadFile(r.fullname)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	got := render("SELECT 1;")
+	lines := strings.Split(got, "\n")
+	if len(lines) < 6 {
+		t.Fatalf("unexpected output:\n%s", got)
+	}
+	want := []string{
+		"-- Code generated by gen version (devel). DO NOT EDIT.",
+		`-- Command: gen -config ` + input + ` -name "two words"`,
+		"-- Inputs: config.yaml",
+	}
+	for i, line := range want {
+		if lines[i] != line {
+			t.Errorf("unexpected header line %d '%s', want '%s'", i+1, lines[i], line)
+		}
+	}
+	if !strings.HasPrefix(lines[3], "-- Inputs hash: sha256:") {
+		t.Errorf("unexpected hash line '%s'", lines[3])
+	}
+
+	if again := render("SELECT 2;"); again != got {
+		t.Errorf("file must not be rewritten while inputs are the same, got:\n%s", again)
+	}
+
+	if err := os.WriteFile(input, []byte("a: 2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if changed := render("SELECT 2;"); !strings.HasSuffix(changed, "SELECT 2;\n") || changed == got {
+		t.Errorf("file must be rewritten after inputs change, got:\n%s", changed)
+	}
+}
+
+func TestAutogenInputsMissing(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("panic expected")
+		}
+	}()
+
+	p := newTestPackage(t)
+	r := p.Raw("query.sql", AutogenInputs("gen", filepath.Join(t.TempDir(), "missing.yaml")))
+	_ = r.render()
+}
@@ -0,0 +1,159 @@
+package gogh
+
+import (
+	"os"
+	"testing"
+)
+
+func TestDefaultRendererOptions(t *testing.T) {
+	p := newTestPackage(t)
+	p.mod.defaultOpts = []RendererOption{
+		Autogen("gen"),
+		License("Copyright 2026 Example Inc."),
+	}
+
+	render := func(r *RawRenderer) string {
+		t.Helper()
+
+		r.R(`SELECT 1;`)
+		if err := r.render(); err != nil {
+			t.Fatal(err)
+		}
+		data, err := os.ReadFile(r.fullname)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	want := `-- Copyright 2026 Example Inc.
+
+-- Code generated by gen version (devel). DO NOT EDIT.
+
+SELECT 1;
+`
+	if got := render(p.Raw("defaults.sql")); got != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", got, want)
+	}
+
+	want = `-- SPDX-FileCopyrightText: 2026 Other Inc.
+-- SPDX-License-Identifier: MIT
+
+SELECT 1;
+`
+	got := render(p.Raw("override.sql", Without(Autogen("")), SPDX("MIT", "Other Inc.", 2026)))
+	if got != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", got, want)
+	}
+
+	p.SetDefaultRendererOptions(Without(License("")))
+	want = `-- Code generated by gen version (devel). DO NOT EDIT.
+
+SELECT 1;
+`
+	if got := render(p.Raw("package.sql")); got != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestDefaultRendererOptionsShy(t *testing.T) {
+	p := newTestPackage(t)
+	p.SetDefaultRendererOptions(Shy)
+
+	r := p.Go("shy.go")
+	r.L(`var _ = 1`)
+	if err := os.WriteFile(r.path(), []byte("package test\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.render(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(r.path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "package test\n" {
+		t.Errorf("existing file must be kept, got:\n%s", data)
+	}
+}
+
+func TestWithValues(t *testing.T) {
+	p := newTestPackage(t)
+
+	raw := p.Raw("values.sql", WithValue("table", "users"), WithValues(map[string]any{"column": "id"}))
+	raw.L(`SELECT $column FROM $table;`)
+	if err := raw.render(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(raw.fullname)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(data), "SELECT id FROM users;\n"; got != want {
+		t.Errorf("unexpected raw output:\n%s\nwant:\n%s", got, want)
+	}
+
+	r := p.Go("values.go", WithValue("name", "user"))
+	r.L(`var ${name|P} int`)
+	const want = `var User int
+`
+	if got := renderedCode(t, r); got != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestDefaultRendererOptionsValues(t *testing.T) {
+	p := newTestPackage(t)
+	p.mod.defaultOpts = []RendererOption{
+		WithValue("a", 1),
+		WithValues(map[string]any{"b": 2, "c": 3}),
+	}
+
+	r := p.Go("values.go", WithValue("b", 20), WithValue("d", 4))
+	for name, want := range map[string]string{
+		"a": "1",
+		"b": "20",
+		"c": "3",
+		"d": "4",
+	} {
+		if !r.InCtx(name) {
+			t.Errorf("value %s is missing", name)
+			continue
+		}
+		if got := r.S("$" + name); got != want {
+			t.Errorf("unexpected value of %s: '%s', want '%s'", name, got, want)
+		}
+	}
+}
+
+func TestDefaultRendererOptionsAppended(t *testing.T) {
+	note := func(text string) RendererOption {
+		return func(r renderingOptionsHandler) bool {
+			r.header().lines = append(r.header().lines, text)
+			return true
+		}
+	}
+
+	p := newTestPackage(t)
+	p.mod.defaultOpts = []RendererOption{note("default note")}
+
+	r := p.Raw("notes.sql", note("file note"))
+	r.R(`SELECT 1;`)
+	if err := r.render(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(r.fullname)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const want = `-- default note
+-- file note
+
+SELECT 1;
+`
+	if string(data) != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", data, want)
+	}
+}
@@ -0,0 +1,271 @@
+package gogh
+
+import (
+	"bytes"
+	"go/types"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/sirkon/errors"
+	"github.com/sirkon/protoast/v2"
+	"github.com/sirkon/protoast/v2/past"
+)
+
+// Proto creates new or reuse existing protobuf file renderer. The file has
+// go_package option pointing to this package and protobuf package named
+// after it unless it is set with ProtoRenderer.Package.
+//
+//	r := pkg.Proto("user.proto").Package("users.v1")
+//	r.L(`message User {`)
+//	r.L(`    $0 created_at = 1;`, timestampType)
+//	r.L(`}`)
+//
+// Produces
+//
+//	syntax = "proto3";
+//
+//	package users.v1;
+//
+//	option go_package = "example.com/project/users;users";
+//
+//	import "google/protobuf/timestamp.proto";
+//
+//	message User {
+//	    google.protobuf.Timestamp created_at = 1;
+//	}
+//
+// The output is parsed back with protoast before it is written.
+func (p *Package[T]) Proto(name string, opts ...RendererOption) *ProtoRenderer {
+	relpath := filepath.ToSlash(filepath.Join(p.rel, name))
+	if v, ok := p.mod.protos[relpath]; ok {
+		return v
+	}
+
+	file := &protoFile{
+		name:      relpath,
+		pkg:       p.name,
+		goPackage: p.Path() + ";" + p.name,
+		imports:   map[string]struct{}{},
+		registry:  p.mod.registry,
+	}
+	res := &ProtoRenderer{
+		RawRenderer: p.mod.raw(relpath, p.defaultRendererOptions(), opts),
+		file:        file,
+	}
+	file.path = res.fullname
+	res.finish = file.finish
+
+	if p.mod.protos == nil {
+		p.mod.protos = map[string]*ProtoRenderer{}
+	}
+	p.mod.protos[relpath] = res
+	return res
+}
+
+// ProtoRenderer rendering of protobuf files. It is a RawRenderer which
+// takes care of the file header, imports and references to protobuf
+// types.
+type ProtoRenderer struct {
+	*RawRenderer
+
+	file *protoFile
+}
+
+type protoFile struct {
+	name      string
+	pkg       string
+	goPackage string
+	imports   map[string]struct{}
+	registry  *protoast.Registry
+
+	// path of the file on disk. Field numbers of messages generated
+	// from Go types are kept between runs in the file itself.
+	path      string
+	goNumbers map[string]protoNumbers
+
+	goTypes   map[*types.TypeName]string
+	goPending []*types.Named
+}
+
+// Package sets protobuf package name of the file.
+func (r *ProtoRenderer) Package(name string) *ProtoRenderer {
+	r.file.pkg = name
+	return r
+}
+
+// Import adds import of the given protobuf file. Imports are deduplicated
+// and sorted.
+func (r *ProtoRenderer) Import(path string) {
+	if path == r.file.name {
+		return
+	}
+
+	r.file.imports[path] = struct{}{}
+}
+
+// Type renders a reference to the given protobuf type from the registry
+// set with WithProtoRegistry. Files defining messages and enums are
+// imported, types of the same protobuf package are referenced with
+// their local names.
+func (r *ProtoRenderer) Type(t past.Type) string {
+	registry := r.file.registry
+	switch v := t.(type) {
+	case *past.Repeated:
+		return "repeated " + r.Type(v.Type)
+	case *past.Map:
+		return "map<" + r.Type(v.Key()) + ", " + r.Type(v.Value(registry)) + ">"
+	case *past.Message, *past.Enum:
+		if registry == nil {
+			panic(errors.New("proto registry is not set, use WithProtoRegistry"))
+		}
+
+		if file := registry.NodeFile(v); file != nil {
+			r.Import(file.Name())
+		}
+
+		name := strings.TrimPrefix(registry.TypeName(v), ".")
+		return strings.TrimPrefix(name, r.file.pkg+".")
+	case past.BuiltinType:
+		return v.String()
+	default:
+		panic(errors.Newf("unsupported protobuf type %T", t))
+	}
+}
+
+// L puts a single formatted line and new line character. Protobuf types
+// in arguments are rendered with Type.
+func (r *ProtoRenderer) L(line string, a ...any) {
+	r.RawRenderer.L(line, r.args(a)...)
+}
+
+// S same as L, just returns string insert of pushing it.
+func (r *ProtoRenderer) S(line string, a ...any) string {
+	return r.RawRenderer.S(line, r.args(a)...)
+}
+
+// Z returns extended ProtoRenderer which will write after the last written
+// line of the current one and before any new line pushed after this call.
+func (r *ProtoRenderer) Z() *ProtoRenderer {
+	return &ProtoRenderer{
+		RawRenderer: r.RawRenderer.Z(),
+		file:        r.file,
+	}
+}
+
+// Scope returns a new renderer with a scope inherited from the original.
+func (r *ProtoRenderer) Scope() *ProtoRenderer {
+	return &ProtoRenderer{
+		RawRenderer: r.RawRenderer.Scope(),
+		file:        r.file,
+	}
+}
+
+// InnerScope creates a new scope and feeds it into the given function.
+func (r *ProtoRenderer) InnerScope(f func(r *ProtoRenderer)) {
+	f(r.Scope())
+}
+
+func (r *ProtoRenderer) args(a []any) []any {
+	res := make([]any, len(a))
+	for i, v := range a {
+		if t, ok := v.(past.Type); ok {
+			res[i] = r.Type(t)
+			continue
+		}
+		res[i] = v
+	}
+
+	return res
+}
+
+// finish prepends the header to the rendered content and checks it is valid.
+func (f *protoFile) finish(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(`syntax = "proto3";` + "\n\n")
+	buf.WriteString("package " + f.pkg + ";\n\n")
+	buf.WriteString("option go_package = " + strconv.Quote(f.goPackage) + ";\n\n")
+
+	if len(f.imports) > 0 {
+		imports := make([]string, 0, len(f.imports))
+		for path := range f.imports {
+			imports = append(imports, path)
+		}
+		slices.Sort(imports)
+		for _, path := range imports {
+			buf.WriteString("import " + strconv.Quote(path) + ";\n")
+		}
+		buf.WriteByte('\n')
+	}
+
+	buf.Write(data)
+
+	if err := checkProtoFile(f.name, buf.Bytes()); err != nil {
+		return nil, errors.Wrap(err, "check rendered protobuf file")
+	}
+
+	return buf.Bytes(), nil
+}
+
+// checkProtoFile parses protobuf file content with protoast. Imported files
+// are not checked, they are replaced with empty stubs.
+func checkProtoFile(name string, data []byte) (err error) {
+	dir, err := os.MkdirTemp("", "gogh-proto-")
+	if err != nil {
+		return errors.Wrap(err, "create temporary directory")
+	}
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	resolver := &protoCheckResolver{
+		name:  name,
+		file:  filepath.Join(dir, "file.proto"),
+		dummy: filepath.Join(dir, "dummy.proto"),
+	}
+	if err := os.WriteFile(resolver.file, data, 0644); err != nil {
+		return errors.Wrap(err, "save content")
+	}
+	if err := os.WriteFile(resolver.dummy, []byte(`syntax = "proto3";`+"\n"), 0644); err != nil {
+		return errors.Wrap(err, "save import stub")
+	}
+
+	// Visitors of protoast panic on errors in imports.
+	defer func() {
+		if p := recover(); p != nil {
+			err = errors.Newf("%v", p)
+		}
+	}()
+
+	registry, err := protoast.NewRegistry([]protoast.PathResolver{resolver})
+	if err != nil {
+		return errors.Wrap(err, "set up registry")
+	}
+	if _, err := registry.Proto(name); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// protoCheckResolver resolves the file under check into its saved content
+// and everything else into an empty stub.
+type protoCheckResolver struct {
+	name  string
+	file  string
+	dummy string
+}
+
+func (r *protoCheckResolver) String() string {
+	return "check of " + r.name
+}
+
+func (r *protoCheckResolver) Resolve(path string) (string, error) {
+	if path == r.name {
+		return r.file, nil
+	}
+
+	return r.dummy, nil
+}
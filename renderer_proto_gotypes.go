@@ -0,0 +1,434 @@
+package gogh
+
+import (
+	"bytes"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"os"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/emicklei/proto"
+	"github.com/sirkon/errors"
+)
+
+// GoType renders protobuf definitions for the given Go named type and all
+// named types it depends on:
+//   - Structs are rendered as messages with fields named with Underscored.
+//     Unexported fields and ones tagged with `proto:"-"` are skipped,
+//     anonymous structs are rendered as nested messages.
+//   - Integer types having constants of them are rendered as enums with
+//     values named like COLOR_RED for ColorRed or Red constants of Color.
+//     COLOR_UNSPECIFIED = 0 is added if there is no zero constant.
+//   - Slices are repeated, pointers to scalars are optional, time.Time and
+//     time.Duration are google.protobuf.Timestamp and google.protobuf.Duration.
+//
+// Field numbers are kept between runs: they are taken from the previously
+// rendered file, so it must be committed along with the code. New fields
+// get numbers after the greatest one used or reserved in the message and
+// numbers of removed fields are reserved.
+//
+// Types are rendered once per file, so it is fine to call GoType for a type
+// that was already rendered as a dependency.
+func (r *ProtoRenderer) GoType(t types.Type) {
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok {
+		panic(errors.Newf("named type expected, got %s", t))
+	}
+
+	if _, ok := r.goNamed(named); !ok {
+		panic(errors.Newf("type %s is neither a struct nor an enum", t))
+	}
+
+	for len(r.file.goPending) > 0 {
+		next := r.file.goPending[0]
+		r.file.goPending = r.file.goPending[1:]
+
+		if r.last().Len() > 0 {
+			r.N()
+		}
+		for _, line := range r.goDefinition(next, "") {
+			r.R(line)
+		}
+	}
+}
+
+// goNamed returns protobuf name of the struct or enum type and schedules
+// its rendering if needed. Returns false for other types.
+func (r *ProtoRenderer) goNamed(t *types.Named) (string, bool) {
+	obj := t.Obj()
+	if name, ok := r.file.goTypes[obj]; ok {
+		return name, true
+	}
+
+	switch u := t.Underlying().(type) {
+	case *types.Struct:
+	case *types.Basic:
+		if u.Info()&types.IsInteger == 0 || len(goEnumConsts(t)) == 0 {
+			return "", false
+		}
+	default:
+		return "", false
+	}
+
+	if r.file.goTypes == nil {
+		r.file.goTypes = map[*types.TypeName]string{}
+	}
+	r.file.goTypes[obj] = obj.Name()
+	r.file.goPending = append(r.file.goPending, t)
+	return obj.Name(), true
+}
+
+// goDefinition renders definition lines of the named struct or enum type.
+func (r *ProtoRenderer) goDefinition(t *types.Named, indent string) []string {
+	name := t.Obj().Name()
+	if s, ok := t.Underlying().(*types.Struct); ok {
+		return r.goMessage(name, name, s, indent)
+	}
+
+	return r.goEnum(name, goEnumConsts(t), indent)
+}
+
+func (r *ProtoRenderer) goMessage(name, fullname string, s *types.Struct, indent string) []string {
+	type field struct {
+		name  string
+		label string
+		typ   string
+	}
+
+	var nested []string
+	var fields []field
+	for i := 0; i < s.NumFields(); i++ {
+		f := s.Field(i)
+		if !f.Exported() || goStructTag(s.Tag(i), "proto") == "-" {
+			continue
+		}
+
+		fname := Underscored(f.Name())
+		if anon, ok := goAnonStruct(f.Type()); ok {
+			// Anonymous structs become nested messages.
+			mname := Proto(fname)
+			nested = append(nested, r.goMessage(mname, fullname+"."+mname, anon, indent+"    ")...)
+			nested = append(nested, "")
+			fields = append(fields, field{name: fname, typ: mname})
+			continue
+		}
+
+		label, typ, err := r.goFieldType(f.Type())
+		if err != nil {
+			panic(errors.Wrapf(err, "field %s.%s", fullname, f.Name()))
+		}
+		fields = append(fields, field{name: fname, label: label, typ: typ})
+	}
+
+	names := make([]string, 0, len(fields))
+	for _, f := range fields {
+		names = append(names, f.name)
+	}
+	numbers, reserved := r.file.goMessageNumbers(fullname, names)
+
+	res := []string{indent + "message " + name + " {"}
+	res = append(res, nested...)
+	if len(reserved) > 0 {
+		parts := make([]string, 0, len(reserved))
+		for _, n := range reserved {
+			parts = append(parts, strconv.Itoa(n))
+		}
+		res = append(res, indent+"    reserved "+strings.Join(parts, ", ")+";", "")
+	}
+	for _, f := range fields {
+		line := indent + "    "
+		if f.label != "" {
+			line += f.label + " "
+		}
+		line += f.typ + " " + f.name + " = " + strconv.Itoa(numbers[f.name]) + ";"
+		res = append(res, line)
+	}
+	if len(res) > 1 && res[len(res)-1] == "" {
+		res = res[:len(res)-1]
+	}
+	res = append(res, indent+"}")
+
+	return res
+}
+
+func (r *ProtoRenderer) goEnum(name string, consts []*types.Const, indent string) []string {
+	prefix := strings.ToUpper(Underscored(name))
+
+	var res []string
+	res = append(res, indent+"enum "+name+" {")
+	if !slices.ContainsFunc(consts, func(c *types.Const) bool {
+		return constant.Sign(c.Val()) == 0
+	}) {
+		res = append(res, indent+"    "+prefix+"_UNSPECIFIED = 0;")
+	}
+	for _, c := range consts {
+		label := strings.TrimPrefix(c.Name(), name)
+		if label == "" {
+			label = c.Name()
+		}
+		res = append(res, indent+"    "+prefix+"_"+strings.ToUpper(Underscored(label))+" = "+c.Val().ExactString()+";")
+	}
+	res = append(res, indent+"}")
+
+	return res
+}
+
+// goFieldType maps Go type to protobuf field label and type.
+func (r *ProtoRenderer) goFieldType(t types.Type) (label string, typ string, err error) {
+	switch v := types.Unalias(t).(type) {
+	case *types.Pointer:
+		if _, ok := types.Unalias(v.Elem()).(*types.Pointer); ok {
+			return "", "", errors.Newf("pointer to pointer %s is not supported", t)
+		}
+
+		label, typ, err := r.goFieldType(v.Elem())
+		if err != nil || label != "" {
+			return label, typ, err
+		}
+		if goIsProtoScalar(typ) {
+			return "optional", typ, nil
+		}
+		return "", typ, nil
+	case *types.Slice:
+		if goIsByte(v.Elem()) {
+			return "", "bytes", nil
+		}
+
+		label, typ, err := r.goFieldType(v.Elem())
+		if err != nil {
+			return "", "", err
+		}
+		if label == "repeated" || strings.HasPrefix(typ, "map<") {
+			return "", "", errors.Newf("nested collection %s is not supported", t)
+		}
+		return "repeated", typ, nil
+	case *types.Map:
+		_, key, err := r.goFieldType(v.Key())
+		if err != nil {
+			return "", "", errors.Wrap(err, "map key")
+		}
+		switch key {
+		case "bool", "string", "int32", "int64", "uint32", "uint64":
+		default:
+			return "", "", errors.Newf("map key type %s is not supported", v.Key())
+		}
+
+		label, value, err := r.goFieldType(v.Elem())
+		if err != nil {
+			return "", "", errors.Wrap(err, "map value")
+		}
+		if label == "repeated" || strings.HasPrefix(value, "map<") {
+			return "", "", errors.Newf("map value type %s is not supported", v.Elem())
+		}
+		return "", "map<" + key + ", " + value + ">", nil
+	case *types.Named:
+		switch goTimeType(v) {
+		case "Time":
+			r.Import("google/protobuf/timestamp.proto")
+			return "", "google.protobuf.Timestamp", nil
+		case "Duration":
+			r.Import("google/protobuf/duration.proto")
+			return "", "google.protobuf.Duration", nil
+		}
+
+		if name, ok := r.goNamed(v); ok {
+			return "", name, nil
+		}
+		return r.goFieldType(v.Underlying())
+	case *types.Basic:
+		switch v.Kind() {
+		case types.Bool:
+			return "", "bool", nil
+		case types.String:
+			return "", "string", nil
+		case types.Int, types.Int64:
+			return "", "int64", nil
+		case types.Int8, types.Int16, types.Int32:
+			return "", "int32", nil
+		case types.Uint, types.Uint64, types.Uintptr:
+			return "", "uint64", nil
+		case types.Uint8, types.Uint16, types.Uint32:
+			return "", "uint32", nil
+		case types.Float32:
+			return "", "float", nil
+		case types.Float64:
+			return "", "double", nil
+		}
+	}
+
+	return "", "", errors.Newf("type %s is not supported", t)
+}
+
+// goMessageNumbers returns field numbers of the message and numbers of its fields
+// removed since the previous run. Numbers are taken from the message of
+// the previously rendered file, so they stay the same between runs.
+func (f *protoFile) goMessageNumbers(message string, fields []string) (map[string]int, []int) {
+	if f.goNumbers == nil {
+		prev, err := protoFileNumbers(f.path)
+		if err != nil {
+			panic(errors.Wrapf(err, "get field numbers of previously rendered %s", f.name))
+		}
+		f.goNumbers = prev
+	}
+	prev := f.goNumbers[message]
+
+	var last int
+	numbers := map[string]int{}
+	for name, n := range prev.fields {
+		last = max(last, n)
+		if slices.Contains(fields, name) {
+			numbers[name] = n
+		}
+	}
+	for _, n := range prev.reserved {
+		last = max(last, n)
+	}
+	for _, name := range fields {
+		if _, ok := numbers[name]; !ok {
+			last++
+			numbers[name] = last
+		}
+	}
+
+	reserved := slices.Clone(prev.reserved)
+	for name, n := range prev.fields {
+		if _, ok := numbers[name]; !ok {
+			reserved = append(reserved, n)
+		}
+	}
+	slices.Sort(reserved)
+
+	return numbers, slices.Compact(reserved)
+}
+
+// protoNumbers field numbers of a message.
+type protoNumbers struct {
+	fields   map[string]int
+	reserved []int
+}
+
+// protoFileNumbers returns field numbers of messages of the protobuf file
+// by their full names within the file. There are no numbers if the file
+// does not exist.
+func protoFileNumbers(path string) (map[string]protoNumbers, error) {
+	res := map[string]protoNumbers{}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return res, nil
+		}
+
+		return nil, errors.Wrap(err, "read file")
+	}
+	parsed, err := proto.NewParser(bytes.NewReader(data)).Parse()
+	if err != nil {
+		return nil, errors.Wrap(err, "parse file")
+	}
+
+	proto.Walk(parsed, proto.WithMessage(func(msg *proto.Message) {
+		if err != nil {
+			return
+		}
+
+		name := msg.Name
+		for p := msg.Parent; p != nil; {
+			parent, ok := p.(*proto.Message)
+			if !ok {
+				break
+			}
+			name = parent.Name + "." + name
+			p = parent.Parent
+		}
+
+		numbers := protoNumbers{
+			fields: map[string]int{},
+		}
+		for _, e := range msg.Elements {
+			switch v := e.(type) {
+			case *proto.NormalField:
+				numbers.fields[v.Name] = v.Sequence
+			case *proto.MapField:
+				numbers.fields[v.Name] = v.Sequence
+			case *proto.Reserved:
+				for _, rng := range v.Ranges {
+					if rng.Max {
+						err = errors.Newf("message %s reserves numbers up to max", name)
+						return
+					}
+					for n := rng.From; n <= rng.To; n++ {
+						numbers.reserved = append(numbers.reserved, n)
+					}
+				}
+			}
+		}
+		res[name] = numbers
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// goEnumConsts returns constants of the given type from its package
+// ordered by their values.
+func goEnumConsts(t *types.Named) []*types.Const {
+	pkg := t.Obj().Pkg()
+	if pkg == nil {
+		return nil
+	}
+
+	var res []*types.Const
+	for _, name := range pkg.Scope().Names() {
+		c, ok := pkg.Scope().Lookup(name).(*types.Const)
+		if !ok || !types.Identical(c.Type(), t) {
+			continue
+		}
+		res = append(res, c)
+	}
+	slices.SortStableFunc(res, func(a, b *types.Const) int {
+		switch {
+		case constant.Compare(a.Val(), token.LSS, b.Val()):
+			return -1
+		case constant.Compare(a.Val(), token.GTR, b.Val()):
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	return res
+}
+
+func goAnonStruct(t types.Type) (*types.Struct, bool) {
+	if p, ok := t.(*types.Pointer); ok {
+		t = p.Elem()
+	}
+	s, ok := t.(*types.Struct)
+	return s, ok
+}
+
+func goIsByte(t types.Type) bool {
+	b, ok := types.Unalias(t).(*types.Basic)
+	return ok && b.Kind() == types.Byte
+}
+
+func goIsProtoScalar(typ string) bool {
+	switch typ {
+	case "bool", "string", "bytes", "int32", "int64", "uint32", "uint64", "float", "double":
+		return true
+	default:
+		return false
+	}
+}
+
+func goStructTag(tag, key string) string {
+	value, _ := reflect.StructTag(tag).Lookup(key)
+	name, _, _ := strings.Cut(value, ",")
+	return name
+}
@@ -0,0 +1,227 @@
+package gogh
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestProtoRendererGoType(t *testing.T) {
+	p := newTestPackage(t)
+
+	pkg := typesOf(t, `package source
+
+import "time"
+
+type Color int
+
+const (
+	ColorRed Color = iota + 1
+	ColorDarkBlue
+)
+
+type ID string
+
+type User struct {
+	ID        ID
+	Name      string
+	Age       *int32
+	Tags      []string
+	Avatar    []byte
+	Scores    map[string]float64
+	Color     Color
+	Address   *Address
+	Addresses []Address
+	CreatedAt time.Time
+	TTL       time.Duration
+	Settings  struct {
+		Theme string
+	}
+
+	Secret string `+"`proto:\"-\"`"+`
+	hidden string
+}
+
+type Address struct {
+	City string
+	Zip  uint16
+}
+`)
+
+	r := p.Proto("users.proto").Package("users.v1")
+	r.GoType(pkg.Scope().Lookup("User").Type())
+	r.GoType(pkg.Scope().Lookup("Address").Type())
+
+	want := `syntax = "proto3";
+
+package users.v1;
+
+option go_package = "example.com/test;test";
+
+import "google/protobuf/duration.proto";
+import "google/protobuf/timestamp.proto";
+
+message User {
+    message Settings {
+        string theme = 1;
+    }
+
+    string id = 1;
+    string name = 2;
+    optional int32 age = 3;
+    repeated string tags = 4;
+    bytes avatar = 5;
+    map<string, double> scores = 6;
+    Color color = 7;
+    Address address = 8;
+    repeated Address addresses = 9;
+    google.protobuf.Timestamp created_at = 10;
+    google.protobuf.Duration ttl = 11;
+    Settings settings = 12;
+}
+
+enum Color {
+    COLOR_UNSPECIFIED = 0;
+    COLOR_RED = 1;
+    COLOR_DARK_BLUE = 2;
+}
+
+message Address {
+    string city = 1;
+    uint32 zip = 2;
+}
+`
+	if got := renderedProto(t, r); got != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestProtoRendererGoTypeNumbers(t *testing.T) {
+	p := newTestPackage(t)
+
+	render := func(src string) string {
+		delete(p.mod.protos, "items.proto")
+		r := p.Proto("items.proto").Package("items.v1")
+		r.GoType(typesOf(t, src).Scope().Lookup("Item").Type())
+		return renderedProto(t, r)
+	}
+
+	render(`package source
+
+type Item struct {
+	Name  string
+	Price int64
+	Count int32
+}
+`)
+	got := render(`package source
+
+type Item struct {
+	Count int32
+	Title string
+	Name  string
+}
+`)
+
+	want := `message Item {
+    reserved 2;
+
+    int32 count = 3;
+    string title = 4;
+    string name = 1;
+}
+`
+	if !strings.HasSuffix(got, want) {
+		t.Errorf("unexpected output:\n%s\nwant suffix:\n%s", got, want)
+	}
+}
+
+func TestProtoRendererGoTypeNumbersFromFile(t *testing.T) {
+	p := newTestPackage(t)
+	r := p.Proto("items.proto").Package("items.v1")
+
+	// The file of a previous run is the only source of numbers.
+	prev := `syntax = "proto3";
+
+package items.v1;
+
+message Item {
+    message Meta {
+        string tag = 2;
+    }
+
+    reserved 2, 5 to 6;
+
+    string name = 1;
+    Meta meta = 4;
+    int32 count = 3;
+}
+`
+	if err := os.WriteFile(r.fullname, []byte(prev), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r.GoType(typesOf(t, `package source
+
+type Item struct {
+	Title string
+	Name  string
+	Meta  struct {
+		Tag  string
+		Note string
+	}
+}
+`).Scope().Lookup("Item").Type())
+
+	want := `message Item {
+    message Meta {
+        string tag = 2;
+        string note = 3;
+    }
+
+    reserved 2, 3, 5, 6;
+
+    string title = 7;
+    string name = 1;
+    Meta meta = 4;
+}
+`
+	if got := renderedProto(t, r); !strings.HasSuffix(got, want) {
+		t.Errorf("unexpected output:\n%s\nwant suffix:\n%s", got, want)
+	}
+}
+
+func TestProtoRendererGoTypeUnsupported(t *testing.T) {
+	p := newTestPackage(t)
+	pkg := typesOf(t, `package source
+
+type Item struct {
+	Matrix [][]int
+}
+`)
+
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatal("panic expected")
+		}
+		if err, ok := r.(error); !ok || !strings.Contains(err.Error(), "field Item.Matrix") {
+			t.Errorf("unexpected panic: %v", r)
+		}
+	}()
+	p.Proto("items.proto").GoType(pkg.Scope().Lookup("Item").Type())
+}
+
+func renderedProto(t *testing.T, r *ProtoRenderer) string {
+	t.Helper()
+
+	if err := r.render(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(r.fullname)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(data)
+}
@@ -0,0 +1,86 @@
+package gogh
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestProtoRenderer(t *testing.T) {
+	p := newTestPackage(t)
+	file := testProtos(t, p)
+	registry := p.mod.registry
+	user := file.Message(registry, "User")
+
+	r := p.Proto("users.proto").Package("users.v1")
+	r.L(`message Profile {`)
+	r.L(`    $0 user = 1;`, user)
+	r.L(`    $0 created_at = 2;`, user.Field(registry, "created_at").Type(registry))
+	r.L(`    $0 roles = 3;`, user.Field(registry, "counters").Type(registry))
+	r.L(`    $0 nick = 4;`, user.Field(registry, "nick").Type(registry))
+	r.L(`    $0 role = 5;`, user.Field(registry, "role").Type(registry))
+	r.L(`}`)
+
+	if err := r.render(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(r.fullname)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `syntax = "proto3";
+
+package users.v1;
+
+option go_package = "example.com/test;test";
+
+import "example/v1/example.proto";
+import "google/protobuf/timestamp.proto";
+import "google/protobuf/wrappers.proto";
+
+message Profile {
+    example.v1.User user = 1;
+    google.protobuf.Timestamp created_at = 2;
+    map<string, int64> roles = 3;
+    google.protobuf.StringValue nick = 4;
+    example.v1.User.Role role = 5;
+}
+`
+	if string(data) != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", data, want)
+	}
+
+	if p.Proto("users.proto") != r {
+		t.Error("existing renderer must be reused")
+	}
+}
+
+func TestProtoRendererLocalNames(t *testing.T) {
+	p := newTestPackage(t)
+	file := testProtos(t, p)
+
+	r := p.Proto("local.proto").Package("example.v1")
+	if got := r.Type(file.Message(p.mod.registry, "User")); got != "User" {
+		t.Errorf("unexpected local name '%s'", got)
+	}
+}
+
+func TestProtoRendererInvalid(t *testing.T) {
+	p := newTestPackage(t)
+
+	r := p.Proto("invalid.proto")
+	r.L(`message Broken {`)
+	r.L(`    string name = ;`)
+
+	err := r.render()
+	if err == nil {
+		t.Fatal("error expected")
+	}
+	if !strings.Contains(err.Error(), "check rendered protobuf file") {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if _, err := os.Stat(r.fullname); !os.IsNotExist(err) {
+		t.Error("invalid file must not be written")
+	}
+}
@@ -2,21 +2,52 @@ package gogh
 
 import (
 	"bytes"
+	"fmt"
 	"io"
+	"maps"
+	"math"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/sirkon/errors"
 	"github.com/sirkon/go-format/v2"
+
+	"github.com/sirkon/gogh/internal/blocks"
 )
 
-// RawRenderer rendering of plain text files
+// RawRenderer rendering of plain text files. It follows the scoping model
+// of GoRenderer: named values set with Let and unique names taken with Uniq
+// or inline @name uniques are visible in the scope they were set in and in
+// its child scopes.
 type RawRenderer struct {
+	modroot   string
 	localname string
 	fullname  string
 	options   []RendererOption
+	finish    func(data []byte) ([]byte, error)
+	format    Formatter
+	hdr       fileHeader
+	cmtStyle  *CommentStyle
+
+	vals      *valScope
+	blocksmgr *blocks.Manager
+	uniqs     map[string]struct{}
+	linebuf   bytes.Buffer
+}
+
+func newRawRenderer(modroot, localname, fullname string, opts []RendererOption) *RawRenderer {
+	res := &RawRenderer{
+		modroot:   modroot,
+		localname: localname,
+		fullname:  fullname,
+		vals:      newEmptyValScope(),
+		blocksmgr: blocks.New(),
+		uniqs:     map[string]struct{}{},
+	}
+	res.options = rendererValues(res, opts)
 
-	vals   map[string]any
-	blocks []*bytes.Buffer
+	return res
 }
 
 // N puts new line character
@@ -24,9 +55,10 @@ func (r *RawRenderer) N() {
 	r.last().WriteByte('\n')
 }
 
-// L puts a single formatted line and new line character
+// L puts a single formatted line and new line character. Inline uniques
+// like @name are supported the same way GoRenderer.L does.
 func (r *RawRenderer) L(line string, a ...any) {
-	renderLine(r.last(), line, r.rendererCtx(), a...)
+	r.renderLine(r.last(), line, a...)
 	r.last().WriteByte('\n')
 }
 
@@ -39,33 +71,122 @@ func (r *RawRenderer) R(line string) {
 // S same as L, just returns string insert of pushing it
 func (r *RawRenderer) S(line string, a ...any) string {
 	var dst bytes.Buffer
-	renderLine(&dst, line, r.rendererCtx(), a...)
+	r.renderLine(&dst, line, a...)
 
 	return dst.String()
 }
 
-// Z returns extended RawRenderer which will write after the last written line of the current one
-// and before any new line pushed after this call.
-func (r *RawRenderer) Z() *RawRenderer {
-	r.last()
+// Uniq is used to generate unique names, it works the same way
+// GoRenderer.Uniq does:
+//
+//	r.Uniq("name")        // name
+//	r.Uniq("name")        // name2
+//	r.Uniq("name", "alt") // nameAlt
+func (r *RawRenderer) Uniq(name string, optSuffix ...string) string {
+	if _, ok := r.uniqs[name]; !ok {
+		r.uniqs[name] = struct{}{}
+		return name
+	}
 
-	vals := make(map[string]any, len(r.vals))
-	for name, value := range r.vals {
-		r.vals[name] = value
+	if len(optSuffix) > 0 {
+		try := name + Public(optSuffix[0])
+		if _, ok := r.uniqs[try]; !ok {
+			r.uniqs[try] = struct{}{}
+			return try
+		}
 	}
 
-	res := &RawRenderer{
-		fullname: r.fullname,
-		vals:     vals,
-		blocks:   r.blocks,
+	for i := 1; i < math.MaxInt; i++ {
+		n := name + strconv.Itoa(i+1)
+		if _, ok := r.uniqs[n]; !ok {
+			r.uniqs[n] = struct{}{}
+			return n
+		}
+	}
+
+	panic(errors.Newf("cannot find scope unique name for given base '%s'", name))
+}
+
+// Taken checks if the given unique name has been taken before.
+func (r *RawRenderer) Taken(name string) bool {
+	_, ok := r.uniqs[name]
+	return ok
+}
+
+// Let adds a named constant into the scope of the renderer.
+// It will panic if you will try to set a different value
+// for the name that exists in the current scope.
+func (r *RawRenderer) Let(name string, value any) {
+	if strings.TrimSpace(name) == "" {
+		panic(errors.New("context name must not be empty or white spaced only"))
 	}
-	r.blocks = append(r.blocks, &bytes.Buffer{})
+
+	if r.vals.CheckScope(name) {
+		panic(errors.Newf("attempt to change context constant %q to a different value", name))
+	}
+
+	r.letSet(name, value)
+}
+
+// TryLet same as Let but without a panic, it just exits
+// when the variable is already there.
+func (r *RawRenderer) TryLet(name string, value any) {
+	if strings.TrimSpace(name) == "" {
+		panic(errors.New("context name must not be empty or white spaced only"))
+	}
+
+	if r.vals.CheckScope(name) {
+		return
+	}
+
+	r.letSet(name, value)
+}
+
+// InCtx checks if this name is already in the rendering context.
+func (r *RawRenderer) InCtx(name string) bool {
+	_, ok := r.vals.Get(name)
+	return ok
+}
+
+// Scope returns a new renderer with a scope inherited from the original.
+// Any scope changes made with this renderer will not reflect into the
+// scope of the original renderer.
+func (r *RawRenderer) Scope() *RawRenderer {
+	res := r.clone()
+	res.vals = r.vals.Next()
+	res.uniqs = maps.Clone(r.uniqs)
+	return res
+}
+
+// InnerScope creates a new scope and feeds it into the given function.
+func (r *RawRenderer) InnerScope(f func(r *RawRenderer)) {
+	f(r.Scope())
+}
+
+// Z returns extended RawRenderer which will write after the last written line of the current one
+// and before any new line pushed after this call. It shares the scope with the current one.
+func (r *RawRenderer) Z() *RawRenderer {
+	res := r.clone()
+	res.blocksmgr = r.blocksmgr.Insert().Prev()
 	return res
 }
 
 // Put puts raw bytes directly.
 func (r *RawRenderer) Put(data []byte) {
-	r.blocks = append(r.blocks, bytes.NewBuffer(data))
+	r.last().Write(data)
+}
+
+func (r *RawRenderer) clone() *RawRenderer {
+	return &RawRenderer{
+		localname: r.localname,
+		fullname:  r.fullname,
+		options:   r.options,
+		finish:    r.finish,
+		format:    r.format,
+		vals:      r.vals,
+		blocksmgr: r.blocksmgr,
+		uniqs:     r.uniqs,
+	}
 }
 
 func (r *RawRenderer) path() string {
@@ -76,29 +197,95 @@ func (r *RawRenderer) localPath() string {
 	return r.localname
 }
 
-func (r *RawRenderer) comment() *bytes.Buffer {
-	panic("makes no sense for raw rendering")
+func (r *RawRenderer) root() string {
+	return r.modroot
+}
+
+func (r *RawRenderer) header() *fileHeader {
+	return &r.hdr
+}
+
+func (r *RawRenderer) setCommentStyle(style CommentStyle) {
+	r.cmtStyle = &style
+}
+
+// withHeader puts the header comment into the data. It goes after
+// the shebang line if there is one.
+func (r *RawRenderer) withHeader(data []byte) ([]byte, error) {
+	if r.hdr.empty() {
+		return data, nil
+	}
+	if r.hdr.build != "" {
+		return nil, errors.New("build constraints are only supported for Go files")
+	}
+
+	style, ok := commentStyleOf(r.fullname)
+	if r.cmtStyle != nil {
+		style, ok = *r.cmtStyle, true
+	}
+	if !ok {
+		return nil, errors.Newf("unknown comment syntax of %s, set it with WithCommentStyle", r.localname)
+	}
+
+	var res bytes.Buffer
+	if bytes.HasPrefix(data, []byte("#!")) {
+		line, rest, _ := bytes.Cut(data, []byte{'\n'})
+		res.Write(line)
+		res.WriteString("\n\n")
+		data = bytes.TrimLeft(rest, "\n")
+	}
+	res.Write(r.hdr.render(style))
+	res.Write(data)
+
+	return res.Bytes(), nil
 }
 
 func (r *RawRenderer) setVals(vals map[string]any) {
 	for name, value := range vals {
-		if v, ok := r.vals[name]; ok && value != v {
-			panic(errors.Newf("attempt to set '%s into different value", name))
-		}
+		r.Let(name, value)
 	}
 }
 
+func (r *RawRenderer) setFormatter(f Formatter) {
+	r.format = f
+}
+
+func (r *RawRenderer) letSet(name string, value any) {
+	switch vv := value.(type) {
+	case string:
+		value = casesFormatter{value: vv}
+	case fmt.Stringer:
+		value = casesFormatter{value: vv.String()}
+	default:
+	}
+
+	r.vals.Set(name, value)
+}
+
 func (r *RawRenderer) last() *bytes.Buffer {
-	if len(r.blocks) == 0 {
-		r.blocks = append(r.blocks, &bytes.Buffer{})
+	return r.blocksmgr.Data()
+}
+
+func (r *RawRenderer) renderLine(dst *bytes.Buffer, line string, a ...any) {
+	r.linebuf.Reset()
+	for part := range inlineUniqueParts(line) {
+		switch part.typ {
+		case inlinePartTypeText:
+			r.linebuf.WriteString(part.val)
+		case inlinePartTypeUnique:
+			r.Let(part.val, r.Uniq(part.val))
+			r.linebuf.WriteString("${")
+			r.linebuf.WriteString(part.val)
+			r.linebuf.WriteByte('}')
+		}
 	}
 
-	return r.blocks[len(r.blocks)-1]
+	renderLine(dst, r.linebuf.String(), r.rendererCtx(), a...)
 }
 
 func (r *RawRenderer) rendererCtx() *format.ContextBuilder {
 	res := format.NewContextBuilder()
-	for name, value := range r.vals {
+	for name, value := range r.vals.Map() {
 		res.Add(name, value)
 	}
 
@@ -113,11 +300,28 @@ func (r *RawRenderer) render() error {
 	}
 
 	var dest bytes.Buffer
-	for _, block := range r.blocks {
+	for _, block := range r.blocksmgr.Collect() {
 		_, _ = io.Copy(&dest, block)
 	}
 
-	if err := os.WriteFile(r.fullname, dest.Bytes(), 0644); err != nil {
+	data := dest.Bytes()
+	if r.finish != nil {
+		var err error
+		if data, err = r.finish(data); err != nil {
+			return err
+		}
+	}
+	data, err := r.withHeader(data)
+	if err != nil {
+		return err
+	}
+	if r.format != nil {
+		if data, err = r.format(data); err != nil {
+			return errors.Wrap(err, "format rendered file")
+		}
+	}
+
+	if err := os.WriteFile(r.fullname, data, 0644); err != nil {
 		return err
 	}
 
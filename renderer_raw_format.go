@@ -0,0 +1,274 @@
+package gogh
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/sirkon/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// WithFormatter sets the formatter of the rendered file. It is applied to
+// the whole file content right before it is written, so it is a good place
+// to validate its syntax as well. JSONFmt, YAMLFmt and MarkdownFmt are
+// provided for raw files.
+//
+// Go files are formatted with the module formatter by default, this
+// option overrides it for the given file.
+func WithFormatter(f Formatter) RendererOption {
+	return func(r renderingOptionsHandler) bool {
+		r.setFormatter(f)
+		return true
+	}
+}
+
+// JSONFmt validates JSON and formats it with two spaces indent.
+func JSONFmt(src []byte) ([]byte, error) {
+	if !json.Valid(src) {
+		var v any
+		err := json.Unmarshal(src, &v)
+		return nil, errors.Wrap(err, "invalid JSON")
+	}
+
+	var dst bytes.Buffer
+	if err := json.Indent(&dst, bytes.TrimSpace(src), "", "  "); err != nil {
+		return nil, errors.Wrap(err, "indent JSON")
+	}
+	dst.WriteByte('\n')
+
+	return dst.Bytes(), nil
+}
+
+// YAMLFmt validates YAML and re-encodes it with two spaces indent and
+// mapping keys sorted. Comments are kept, multiple documents are supported.
+// Mappings holding anchors or aliases keep their keys order, as an alias
+// must follow its anchor.
+func YAMLFmt(src []byte) ([]byte, error) {
+	var dst bytes.Buffer
+	enc := yaml.NewEncoder(&dst)
+	enc.SetIndent(2)
+
+	dec := yaml.NewDecoder(bytes.NewReader(src))
+	for {
+		var doc yaml.Node
+		if err := dec.Decode(&doc); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, errors.Wrap(err, "invalid YAML")
+		}
+
+		yamlSortKeys(&doc)
+		if err := enc.Encode(&doc); err != nil {
+			return nil, errors.Wrap(err, "encode YAML")
+		}
+	}
+	if err := enc.Close(); err != nil {
+		return nil, errors.Wrap(err, "encode YAML")
+	}
+
+	// Make sure the result is still valid.
+	dec = yaml.NewDecoder(bytes.NewReader(dst.Bytes()))
+	for {
+		var doc yaml.Node
+		if err := dec.Decode(&doc); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, errors.Wrap(err, "check formatted YAML")
+		}
+	}
+
+	return dst.Bytes(), nil
+}
+
+// yamlSortKeys sorts mapping keys of the node and returns if it has
+// anchors or aliases within.
+func yamlSortKeys(node *yaml.Node) bool {
+	refs := node.Anchor != "" || node.Kind == yaml.AliasNode
+	for _, n := range node.Content {
+		if yamlSortKeys(n) {
+			refs = true
+		}
+	}
+	if node.Kind != yaml.MappingNode || refs {
+		return refs
+	}
+
+	pairs := make([][2]*yaml.Node, 0, len(node.Content)/2)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		pairs = append(pairs, [2]*yaml.Node{node.Content[i], node.Content[i+1]})
+	}
+	slices.SortStableFunc(pairs, func(a, b [2]*yaml.Node) int {
+		// Merge keys must precede keys they may be overridden with.
+		switch {
+		case a[0].Value == "<<" && b[0].Value != "<<":
+			return -1
+		case a[0].Value != "<<" && b[0].Value == "<<":
+			return 1
+		}
+		return strings.Compare(a[0].Value, b[0].Value)
+	})
+
+	node.Content = node.Content[:0]
+	for _, p := range pairs {
+		node.Content = append(node.Content, p[0], p[1])
+	}
+
+	return false
+}
+
+// MarkdownFmt validates tables of Markdown document and aligns their columns.
+// Every row of a table must have the same number of cells as its header
+// and the header must be followed by a delimiter row. Fenced code blocks
+// and other lines are kept as is.
+func MarkdownFmt(src []byte) ([]byte, error) {
+	lines := strings.Split(string(src), "\n")
+
+	var dst strings.Builder
+	var fenced bool
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fenced = !fenced
+		}
+		if fenced || !strings.HasPrefix(trimmed, "|") {
+			dst.WriteString(line)
+			if i < len(lines)-1 {
+				dst.WriteByte('\n')
+			}
+			continue
+		}
+
+		start := i
+		for i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), "|") {
+			i++
+		}
+		table, err := markdownTable(lines[start:i])
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid table at line %d", start+1)
+		}
+		dst.WriteString(table)
+		if i < len(lines) {
+			dst.WriteByte('\n')
+		}
+		i--
+	}
+
+	return []byte(dst.String()), nil
+}
+
+var markdownDelimiterCell = regexp.MustCompile(`^:?-+:?$`)
+
+func markdownTable(lines []string) (string, error) {
+	rows := make([][]string, len(lines))
+	for i, line := range lines {
+		rows[i] = markdownTableCells(line)
+	}
+
+	if len(rows) < 2 {
+		return "", errors.New("table must have a header and a delimiter row")
+	}
+	columns := len(rows[0])
+	for i, row := range rows {
+		if len(row) != columns {
+			return "", errors.Newf("row %d has %d cells while the header has %d", i+1, len(row), columns)
+		}
+	}
+	for _, cell := range rows[1] {
+		if !markdownDelimiterCell.MatchString(cell) {
+			return "", errors.Newf("invalid delimiter row cell '%s'", cell)
+		}
+	}
+
+	widths := make([]int, columns)
+	for i, row := range rows {
+		if i == 1 {
+			continue
+		}
+		for j, cell := range row {
+			widths[j] = max(widths[j], utf8.RuneCountInString(cell))
+		}
+	}
+	for j := range widths {
+		widths[j] = max(widths[j], 3)
+	}
+
+	var dst strings.Builder
+	for i, row := range rows {
+		if i > 0 {
+			dst.WriteByte('\n')
+		}
+		dst.WriteByte('|')
+		for j, cell := range row {
+			dst.WriteByte(' ')
+			if i == 1 {
+				dst.WriteString(markdownDelimiter(cell, widths[j]))
+			} else {
+				dst.WriteString(cell)
+				dst.WriteString(strings.Repeat(" ", widths[j]-utf8.RuneCountInString(cell)))
+			}
+			dst.WriteString(" |")
+		}
+	}
+
+	return dst.String(), nil
+}
+
+// markdownTableCells splits table row into trimmed cells. Escaped pipes
+// are kept within cells.
+func markdownTableCells(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = line[:len(line)-1]
+	}
+
+	var res []string
+	var cell strings.Builder
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && i+1 < len(line) && line[i+1] == '|':
+			cell.WriteString(`\|`)
+			i++
+		case line[i] == '|':
+			res = append(res, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteByte(line[i])
+		}
+	}
+	res = append(res, strings.TrimSpace(cell.String()))
+
+	return res
+}
+
+func markdownDelimiter(cell string, width int) string {
+	left := strings.HasPrefix(cell, ":")
+	right := strings.HasSuffix(cell, ":")
+
+	dashes := width
+	if left {
+		dashes--
+	}
+	if right {
+		dashes--
+	}
+
+	var res string
+	if left {
+		res += ":"
+	}
+	res += strings.Repeat("-", dashes)
+	if right {
+		res += ":"
+	}
+
+	return res
+}
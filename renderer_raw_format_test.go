@@ -0,0 +1,172 @@
+package gogh
+
+import (
+	"os"
+	"testing"
+)
+
+func TestRawFormatters(t *testing.T) {
+	type test struct {
+		name    string
+		format  Formatter
+		input   string
+		want    string
+		wantErr bool
+	}
+
+	tests := []test{
+		{
+			name:   "json",
+			format: JSONFmt,
+			input:  `{"name":"gogh","tags":["a","b"],"nested":{"ok":true}}`,
+			want: `{
+  "name": "gogh",
+  "tags": [
+    "a",
+    "b"
+  ],
+  "nested": {
+    "ok": true
+  }
+}
+`,
+		},
+		{
+			name:    "json invalid",
+			format:  JSONFmt,
+			input:   `{"name":}`,
+			wantErr: true,
+		},
+		{
+			name:   "yaml",
+			format: YAMLFmt,
+			input: `version: 2
+# services
+services:
+    web:
+        ports: [80, 443]
+        image: nginx
+---
+b: 1
+a: 2
+`,
+			want: `# services
+services:
+  web:
+    image: nginx
+    ports: [80, 443]
+version: 2
+---
+a: 2
+b: 1
+`,
+		},
+		{
+			name:   "yaml anchors",
+			format: YAMLFmt,
+			input: `zeta: &x
+  k: 1
+alpha: *x
+nested:
+  b: 1
+  a: 2
+`,
+			want: `zeta: &x
+  k: 1
+alpha: *x
+nested:
+  a: 2
+  b: 1
+`,
+		},
+		{
+			name:    "yaml invalid",
+			format:  YAMLFmt,
+			input:   "a: [1, 2\n",
+			wantErr: true,
+		},
+		{
+			name:   "markdown",
+			format: MarkdownFmt,
+			input: `# Methods
+
+|Method|Description|
+|:-|-:|
+| ` + "`L`" + ` | Renders a line |
+|R|Puts raw text \| as is|
+
+` + "```" + `
+|not|a table|
+` + "```" + `
+`,
+			want: `# Methods
+
+| Method | Description            |
+| :----- | ---------------------: |
+| ` + "`L`" + `    | Renders a line         |
+| R      | Puts raw text \| as is |
+
+` + "```" + `
+|not|a table|
+` + "```" + `
+`,
+		},
+		{
+			name:    "markdown cells mismatch",
+			format:  MarkdownFmt,
+			input:   "| a | b |\n| - | - |\n| 1 |\n",
+			wantErr: true,
+		},
+		{
+			name:    "markdown no delimiter",
+			format:  MarkdownFmt,
+			input:   "| a | b |\n| 1 | 2 |\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.format([]byte(tt.input))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("error expected, got:\n%s", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("unexpected output:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRawRendererWithFormatter(t *testing.T) {
+	p := newTestPackage(t)
+
+	r := p.Raw("config.json", WithFormatter(JSONFmt))
+	r.L(`{"name": $0}`, Q("gogh"))
+	if err := r.render(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(r.fullname)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "{\n  \"name\": \"gogh\"\n}\n"; string(data) != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", data, want)
+	}
+
+	broken := p.Raw("broken.json", WithFormatter(JSONFmt))
+	broken.R(`{"name":`)
+	if err := broken.render(); err == nil {
+		t.Error("error expected")
+	}
+	if _, err := os.Stat(broken.fullname); !os.IsNotExist(err) {
+		t.Error("invalid file must not be written")
+	}
+}
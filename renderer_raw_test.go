@@ -0,0 +1,108 @@
+package gogh
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestRawRenderer(t *testing.T) {
+	p := newTestPackage(t)
+
+	r := p.Raw("query.sql")
+	r.Let("table", "user_accounts")
+	r.L(`SELECT @id FROM $table;`)
+	header := r.Z()
+	r.InnerScope(func(r *RawRenderer) {
+		r.Let("table", "user_roles")
+		r.L(`SELECT @id FROM $table;`)
+		r.L(`SELECT $id FROM ${table|P};`)
+	})
+	r.L(`SELECT $id FROM $table;`)
+	header.L(`-- $0 for $table`, "header")
+
+	if err := r.render(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(r.fullname)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `SELECT id FROM user_accounts;
+-- header for user_accounts
+SELECT id2 FROM user_roles;
+SELECT id2 FROM UserRoles;
+SELECT id FROM user_accounts;
+`
+	if string(data) != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", data, want)
+	}
+}
+
+func TestRawRendererUniq(t *testing.T) {
+	p := newTestPackage(t)
+
+	r := p.Raw("names.txt")
+	if got := r.Uniq("name"); got != "name" {
+		t.Errorf("unexpected unique name '%s'", got)
+	}
+	if got := r.Uniq("name", "alt"); got != "nameAlt" {
+		t.Errorf("unexpected unique name '%s'", got)
+	}
+	if got := r.Uniq("name"); got != "name2" {
+		t.Errorf("unexpected unique name '%s'", got)
+	}
+
+	s := r.Scope()
+	s.Uniq("scoped")
+	if r.Taken("scoped") {
+		t.Error("unique name taken in a child scope must not leak into parent")
+	}
+	if !r.Z().Taken("name2") {
+		t.Error("lazy renderer must share unique names")
+	}
+}
+
+func TestRawRendererLet(t *testing.T) {
+	p := newTestPackage(t)
+
+	r := p.Raw("let.txt")
+	r.Let("name", "value")
+	r.TryLet("name", "other")
+	if got := r.S(`$name`); got != "value" {
+		t.Errorf("unexpected value '%s'", got)
+	}
+
+	defer func() {
+		v := recover()
+		if v == nil {
+			t.Fatal("panic expected")
+		}
+		if err, ok := v.(error); !ok || !strings.Contains(err.Error(), "attempt to change context constant") {
+			t.Errorf("unexpected panic: %v", v)
+		}
+	}()
+	r.Let("name", "other")
+}
+
+func TestRawRendererOptions(t *testing.T) {
+	p := newTestPackage(t)
+
+	r := p.Raw("shy.txt", Shy)
+	r.R(`generated`)
+	if err := os.WriteFile(r.fullname, []byte("manual\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Z().render(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(r.fullname)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "manual\n" {
+		t.Errorf("existing file must be kept, got:\n%s", data)
+	}
+}